@@ -0,0 +1,38 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"net/url"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Decode reads the parameters into a new value of the struct type T, which avoids the declaration of
+// a target variable:
+//
+//	page, err := qparam.Decode[Page](reader, values)
+//
+// Errors are reported like the errors of Read. If a MultiError is returned, the returned value
+// contains all fields which could be read nevertheless. T must be a struct type.
+func Decode[T any](r *Reader, params url.Values) (T, error) {
+	var target T
+	if reflect.TypeOf(target) == nil || reflect.TypeOf(target).Kind() != reflect.Struct {
+		return target, errors.Errorf("type %T is not a struct", target)
+	}
+
+	err := r.Read(params, &target)
+	return target, err
+}
+
+// MustDecode is like Decode but panics if the parameters can't be read. It simplifies the
+// initialization of test data and global variables.
+func MustDecode[T any](r *Reader, params url.Values) T {
+	target, err := Decode[T](r, params)
+	if err != nil {
+		panic(`qparam: Decode(` + params.Encode() + `): ` + err.Error())
+	}
+	return target
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	type page struct {
+		Limit  int `param:",required"`
+		Offset int
+	}
+
+	reader := qparam.NewReader()
+
+	t.Run("valid", func(t *testing.T) {
+		p, err := qparam.Decode[page](reader, url.Values{"limit": []string{"25"}, "offset": []string{"100"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, page{Limit: 25, Offset: 100}, p)
+	})
+
+	t.Run("field errors", func(t *testing.T) {
+		p, err := qparam.Decode[page](reader, url.Values{"offset": []string{"100"}})
+
+		require.Error(t, err)
+		_, ok := err.(qparam.MultiError)
+		assert.True(t, ok, "not a MultiError")
+		assert.Equal(t, page{Offset: 100}, p)
+	})
+
+	t.Run("no struct", func(t *testing.T) {
+		_, err := qparam.Decode[*page](reader, url.Values{})
+		assert.Error(t, err)
+
+		_, err = qparam.Decode[interface{}](reader, url.Values{})
+		assert.Error(t, err)
+	})
+
+	t.Run("must decode", func(t *testing.T) {
+		p := qparam.MustDecode[page](reader, url.Values{"limit": []string{"25"}})
+		assert.Equal(t, page{Limit: 25}, p)
+
+		assert.Panics(t, func() {
+			qparam.MustDecode[page](reader, url.Values{"limit": []string{"many"}})
+		})
+	})
+}
@@ -31,7 +31,8 @@ of one (or more) target struct:
 The following field types are supported: int, int8, int16, int32, int64, uint, uint8, uint16,
 uint32, uint64, float32, float64, bool, string. In addition the package handles also all types
 implementing the TextUnmarshaler interface from the encoding package. Furthermore pointers and
-slices of all before mentioned types are supported.
+slices of all before mentioned types are supported, as well as maps with keys and values of those
+types. Parameters for maps use the keys "name.key" or "name[key]", e.g. filter.status=open.
 
 To handle hierarchically structured data, the package can also be used to read values into fields
 of nested structs. In such a case the keys of the source must use dots as some kind of path
@@ -57,7 +58,201 @@ delimiter:
 	reader := qparam.NewReader()
 	reader.Read(values, &contact)
 
+The separator can be changed using the option Separator. Alternatively the option Brackets enables the
+bracket notation used by many web frameworks and libraries (e.g. "phone[label]"). Slices of structs are
+read from parameters with indexes, e.g. "items.0.name" or "items[0][name]". Elements are ordered by
+their index and gaps between indexes are removed. Indexes greater than 1000 are rejected by default,
+the limit can be changed using the option MaxIndex.
+
+Fields of embedded structs (and pointers to structs) are promoted to the level of the embedding struct
+like encoding/json does it: embedding Pagination with a field Limit results in the parameter "limit".
+Name conflicts are resolved using the same rules as encoding/json. Embedded structs with a name in the
+tag are treated like regular nested structs, e.g. `param:"page"` results in the parameter "page.limit".
+
+Instead of passing a pointer to a target, the generic function Decode returns a new value of the target type:
+
+	page, err := qparam.Decode[Page](reader, values)
+
 The reader can further be configured to use custom field tags and a custom name mapping, which keeps
 the necessity to add tags to struct fields at a minimum (check the examples for more details).
+
+By default the elements of slices are passed as repeated parameters (?id=1&id=2). Other collection
+formats, which separate the elements of a slice by a delimiter (e.g. ?id=1,2), can either be configured
+for all slices of a reader with the option Collection or for single fields with a tag option:
+
+	type Filters struct {
+		IDs   []int    `param:"ids,csv"`
+		Names []string `param:"names,pipes"`
+	}
+
+Default values for missing parameters can be declared using the tag "default". Default values of slices
+are separated by commas. Invalid default values are reported by the first call of Read for the respective
+struct type:
+
+	type Page struct {
+		Limit  int `default:"25"`
+		Offset int
+	}
+
+Parameters which must not be missing can be marked with the tag option "required". For each missing
+required parameter the MultiError returned by Read contains a MissingParameterError:
+
+	type Page struct {
+		Limit  int `param:"limit,required"`
+		Offset int `param:",required"`
+	}
+
+Fields of the type time.Duration are parsed using time.ParseDuration (e.g. "1h30m"). Fields of the type
+time.Time are parsed as RFC 3339 by default. Other layouts can be set per field using the tag "layout" or
+for all time fields of a reader using the option TimeLayouts, where multiple layouts are tried in order.
+The layouts Unix and UnixMilli read times as seconds or milliseconds since January 1, 1970 UTC. Times
+without time zone are interpreted in the location set by the option Location (default: UTC):
+
+	type Report struct {
+		From    time.Time     `layout:"2006-01-02"`
+		Since   time.Time     `layout:"unix"`
+		Timeout time.Duration `default:"30s"`
+	}
+
+	reader := qparam.NewReader(qparam.TimeLayouts(time.RFC3339, qparam.Unix), qparam.Location(loc))
+
+Parameter names must match the field names exactly, unless the option CaseInsensitive is enabled. Fields
+can have further names using the tag option "alias", e.g. for names used by older clients. Aliases must
+not conflict with the names or aliases of other fields. The name of the field takes precedence over the
+aliases, which take precedence in the order of the tag. Parameters with more than one name of the same
+field are rejected by an AliasConflictError if the option AliasConflicts is enabled:
+
+	type Page struct {
+		Limit int `param:"limit,alias=per_page|pageSize"`
+	}
+
+	reader := qparam.NewReader(qparam.CaseInsensitive(true), qparam.AliasConflicts(true))
+
+Names of fields can be marked as deprecated using the tag option "deprecated", which deprecates all names
+of a field, or "deprecated=" followed by the deprecated names separated by "|". Deprecated names are still
+read, but ReadWithResult and ReadRequestWithResult report a Warning for each deprecated name which was
+used, e.g. in order to set the http header Warning or to collect metrics:
+
+	type Page struct {
+		Limit int `param:"limit,alias=per_page,deprecated=per_page"`
+	}
+
+	result, err := reader.ReadWithResult(values, &page)
+
+The result of ReadWithResult also tells which fields were assigned a value from the parameters, which
+allows to distinguish "?limit=0" from a missing parameter, e.g. for partial updates. Furthermore it lists
+the consumed parameters and the parameters which don't belong to any field, also if the reader is not in
+strict mode:
+
+	if result.IsAssigned("limit") {
+		settings.Limit = params.Limit
+	}
+
+Fields which accept a single value report a MultipleValuesError if the parameter is repeated. The option
+MultipleValues or the tag "multiple" select another policy: FirstValue ("first"), LastValue ("last") or,
+for strings only, JoinValues(sep) ("join:" followed by the separator):
+
+	type Search struct {
+		Sort string `multiple:"last"`
+		Tags string `multiple:"join:,"`
+	}
+
+	reader := qparam.NewReader(qparam.MultipleValues(qparam.FirstValue))
+
+Bool fields accept the values of strconv.ParseBool by default. The option Bools enables further values:
+LenientBool accepts also yes/no, y/n and on/off (the value of checked HTML checkboxes), PresenceBool
+additionally treats bool fields as flags, which are true for empty values (e.g. "?verbose") and false if
+the parameter is missing (e.g. an unchecked checkbox):
+
+	reader := qparam.NewReader(qparam.Bools(qparam.PresenceBool))
+
+Constraints for parsed values can be declared using the tags "min" and "max" (numbers), "minlen" and
+"maxlen" (number of characters of strings), "oneof" (allowed values separated by "|") and "pattern"
+(a regular expression which must match the parameter value). For slices these constraints apply to each
+element, while "maxitems" limits the number of elements:
+
+	type Search struct {
+		Limit int      `min:"1" max:"100" default:"25"`
+		Sort  string   `oneof:"asc|desc"`
+		Code  string   `pattern:"^[A-Z]{2}$"`
+		Tags  []string `maxitems:"10" maxlen:"20"`
+	}
+
+Violated constraints are reported by a ConstraintError for the respective parameter.
+
+After all parameters were read successfully, the reader calls the method Validate() error of nested
+structs, slice elements and the target itself (bottom-up), followed by the function registered with the
+option Validator. Errors for specific fields can be reported using a FieldError, which is then added to
+the MultiError as ValidationError with the name of the respective parameter:
+
+	func (s *Search) Validate() error {
+		if s.From.After(s.To) {
+			return &qparam.FieldError{Field: "From", Err: errors.New("from must be before to")}
+		}
+		return nil
+	}
+
+Errors which occur while reading specific parameters are collected in a MultiError. The errors contained
+in a MultiError have one of the types ParseError, MissingParameterError, UnknownParameterError,
+MultipleValuesError, AliasConflictError, UnsupportedTypeError, IndexLimitError, ConstraintError or
+ValidationError. Since a MultiError unwraps to the contained errors, they can also be inspected using
+errors.As:
+
+	var parseErr *qparam.ParseError
+	if errors.As(err, &parseErr) {
+		fmt.Printf("invalid value for %s: %s", parseErr.Param, parseErr.Value)
+	}
+
+Types which neither are supported out of the box nor implement the TextUnmarshaler interface can be
+handled by custom parsers. Custom parsers are registered for a single reader using the options WithParser,
+WithKindParser or WithMatchParser:
+
+	reader := qparam.NewReader(qparam.WithParser(reflect.TypeOf(decimal.Decimal{}), func(s string) (interface{}, error) {
+		return decimal.NewFromString(s)
+	}))
+
+Parameters of http requests can be read directly using ReadRequest. By default the URL query and url
+encoded form values of the request body are read, the option Request allows to read the query, the
+body or multipart forms only. The size of request bodies is limited by the option MaxBodySize:
+
+	reader := qparam.NewReader(qparam.Request(qparam.Multipart), qparam.MaxBodySize(1<<20))
+	err := reader.ReadRequest(req, &search)
+
+With the request source Multipart, uploaded files are read into fields of the type *multipart.FileHeader
+or []*multipart.FileHeader. The tags "maxsize" (in bytes) and "accept" limit the size and content type of
+each file, violations are reported by a ConstraintError like other parameter errors:
+
+	type Upload struct {
+		Title  string
+		Avatar *multipart.FileHeader `param:",required" maxsize:"1048576" accept:"image/png|image/jpeg"`
+	}
+
+ReadRequest also reads headers and cookies into fields with the tags "header" and "cookie". Header names
+are canonicalized, cookie names must match exactly. Errors for such fields are keyed by the name of the
+source and the header or cookie name, e.g. "header:X-Request-Id":
+
+	type Search struct {
+		Query     string `param:"q"`
+		RequestID string `header:"X-Request-ID"`
+		Session   string `cookie:"session"`
+	}
+
+Path values are read into fields with the tag "path", e.g. `path:"id"`. If compiled with Go 1.22 or later,
+ReadRequest reads the path values of the http.ServeMux by default. Path values of other routers can be
+provided using the option PathValues and a Source, e.g. MapSource for a map[string]string. A Source can
+also be read directly using ReadSource, which works like Read but consumes a Source instead of url.Values.
+
+The function Schema describes the parameters of a struct as OpenAPI 3 parameter objects, which can be
+serialized to JSON or YAML. Names, types, collection formats, default values and constraints are derived
+from the struct and the configuration of the reader, descriptions are taken from the tag "doc":
+
+	params, err := qparam.Schema(reader, &Search{})
+
+A Writer does the opposite of a reader and converts the fields of structs back into query parameters.
+The writer accepts the same options as the reader. Therefore the parameters written by a writer can be
+read back into a struct of the same type by an equally configured reader:
+
+	writer := qparam.NewWriter()
+	values, err := writer.Write(&contact)
 */
 package qparam
@@ -0,0 +1,173 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ParseError is reported if the value of a parameter can't be parsed into the type of the
+// target field. For slice fields the error refers to the first value which could not be parsed.
+type ParseError struct {
+	Param      string
+	Value      string
+	TargetType reflect.Type
+	Cause      error
+}
+
+// Error returns the error message
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("invalid value %q for type %s: %s", err.Value, err.TargetType, err.Cause)
+}
+
+// Unwrap returns the error which was returned by the parser.
+func (err *ParseError) Unwrap() error {
+	return err.Cause
+}
+
+// MissingParameterError is reported for each parameter which is required (tag option "required")
+// but is missing. Parameters with an empty list of values are also considered missing.
+type MissingParameterError struct {
+	Param string
+}
+
+// Error returns the error message
+func (err *MissingParameterError) Error() string {
+	return "missing required parameter"
+}
+
+// UnknownParameterError is reported in strict mode for each parameter which has no matching
+// target field.
+type UnknownParameterError struct {
+	Param string
+}
+
+// Error returns the error message
+func (err *UnknownParameterError) Error() string {
+	return "unknown parameter name"
+}
+
+// AliasConflictError is reported if the option AliasConflicts is enabled and parameters for the same
+// field are present under more than one name, e.g. its name and an alias. Names contains the names in
+// order of precedence.
+type AliasConflictError struct {
+	Param string
+	Names []string
+}
+
+// Error returns the error message
+func (err *AliasConflictError) Error() string {
+	return fmt.Sprintf("conflicting parameters %q for the same field", err.Names)
+}
+
+// MultipleValuesError is reported if a parameter has more than one value, but the target field
+// can only hold a single value and the multiple value policy of the field is RejectMultiple (see
+// MultipleValues).
+type MultipleValuesError struct {
+	Param  string
+	Values []string
+}
+
+// Error returns the error message
+func (err *MultipleValuesError) Error() string {
+	return "multiple values for single value parameter"
+}
+
+// UnsupportedTypeError is reported if a parameter refers to a field with a type that can't be
+// read or written.
+type UnsupportedTypeError struct {
+	Param string
+	Type  reflect.Type
+}
+
+// Error returns the error message
+func (err *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("field type %s is not supported", err.Type)
+}
+
+// IndexLimitError is reported for each parameter which refers to a slice element with an index
+// greater than the maximum index (see option MaxIndex).
+type IndexLimitError struct {
+	Param string
+	Index int
+	Max   int
+}
+
+// Error returns the error message
+func (err *IndexLimitError) Error() string {
+	return fmt.Sprintf("index %d exceeds the maximum index %d", err.Index, err.Max)
+}
+
+// ConstraintError is reported if a parsed value violates a constraint which is declared by a struct
+// tag, e.g. `min:"1"`. For the constraint maxitems, Value is the number of slice elements. For the
+// constraints maxsize and accept of uploaded files, Value is the name of the file.
+type ConstraintError struct {
+	Param      string
+	Value      string
+	Constraint string
+	Limit      string
+}
+
+// Error returns the error message
+func (err *ConstraintError) Error() string {
+	return fmt.Sprintf("value %q violates constraint %s:%q", err.Value, err.Constraint, err.Limit)
+}
+
+// FieldError can be returned by Validate methods and validators in order to report an error for a
+// specific field. Field is the Go name of the field relative to the validated struct, names of nested
+// fields are separated by dots (e.g. "Period.From"). Multiple field errors can be returned by an error
+// which unwraps to a slice of errors, like errors created by errors.Join.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error returns the error message
+func (err *FieldError) Error() string {
+	return err.Field + ": " + err.Err.Error()
+}
+
+// Unwrap returns the error for the field.
+func (err *FieldError) Unwrap() error {
+	return err.Err
+}
+
+// ValidationError is reported for errors returned by Validate methods and validators. Errors for specific
+// fields (see FieldError) are reported with the name of the parameter of the field. Other errors are
+// reported with the name of the validated struct, which is the name of the type converted by the mapper
+// for the targets of Read (empty for anonymous structs). Several errors with the same name are joined
+// into a single ValidationError, whose cause then unwraps to all of them.
+type ValidationError struct {
+	Param string
+	Cause error
+}
+
+// Error returns the error message
+func (err *ValidationError) Error() string {
+	return err.Cause.Error()
+}
+
+// Unwrap returns the error which was returned by the Validate method or validator.
+func (err *ValidationError) Unwrap() error {
+	return err.Cause
+}
+
+// joinedErrors combines the causes of several validation errors with the same name
+type joinedErrors []error
+
+// Error returns the messages of all errors separated by semicolons
+func (errs joinedErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the combined errors.
+func (errs joinedErrors) Unwrap() []error {
+	return errs
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	type target struct {
+		Limit   int `param:",required"`
+		Offset  int
+		Sort    string
+		Numbers []uint8
+		Complex complex128
+	}
+
+	values := url.Values{
+		"offset":  []string{"ten"},
+		"sort":    []string{"name", "age"},
+		"numbers": []string{"1", "1000"},
+		"complex": []string{"1+2i"},
+		"unknown": []string{"foo"},
+	}
+
+	reader := qparam.NewReader(qparam.Strict(true))
+	err := reader.Read(values, &target{})
+	require.Error(t, err)
+
+	multi, ok := err.(qparam.MultiError)
+	require.True(t, ok, "not a MultiError")
+	errs := multi.ErrorMap()
+	require.Equal(t, 6, len(errs))
+
+	t.Run("parse error", func(t *testing.T) {
+		var parseErr *qparam.ParseError
+		require.True(t, errors.As(errs["offset"], &parseErr))
+		assert.Equal(t, "offset", parseErr.Param)
+		assert.Equal(t, "ten", parseErr.Value)
+		assert.Equal(t, reflect.TypeOf(0), parseErr.TargetType)
+
+		var numErr *strconv.NumError
+		assert.True(t, errors.As(errs["offset"], &numErr))
+
+		require.True(t, errors.As(errs["numbers"], &parseErr))
+		assert.Equal(t, "numbers", parseErr.Param)
+		assert.Equal(t, "1000", parseErr.Value)
+		assert.Equal(t, reflect.TypeOf(uint8(0)), parseErr.TargetType)
+	})
+
+	t.Run("missing parameter error", func(t *testing.T) {
+		var missingErr *qparam.MissingParameterError
+		require.True(t, errors.As(errs["limit"], &missingErr))
+		assert.Equal(t, "limit", missingErr.Param)
+	})
+
+	t.Run("multiple values error", func(t *testing.T) {
+		var multipleErr *qparam.MultipleValuesError
+		require.True(t, errors.As(errs["sort"], &multipleErr))
+		assert.Equal(t, "sort", multipleErr.Param)
+		assert.Equal(t, []string{"name", "age"}, multipleErr.Values)
+	})
+
+	t.Run("unsupported type error", func(t *testing.T) {
+		var unsupportedErr *qparam.UnsupportedTypeError
+		require.True(t, errors.As(errs["complex"], &unsupportedErr))
+		assert.Equal(t, "complex", unsupportedErr.Param)
+		assert.Equal(t, reflect.TypeOf(complex128(0)), unsupportedErr.Type)
+	})
+
+	t.Run("unknown parameter error", func(t *testing.T) {
+		var unknownErr *qparam.UnknownParameterError
+		require.True(t, errors.As(errs["unknown"], &unknownErr))
+		assert.Equal(t, "unknown", unknownErr.Param)
+	})
+
+	t.Run("index limit error", func(t *testing.T) {
+		target := struct{ IDs []int }{}
+		reader := qparam.NewReader(qparam.MaxIndex(5), qparam.Brackets(true))
+		err := reader.Read(url.Values{"ids[6]": []string{"1"}}, &target)
+		require.Error(t, err)
+
+		var limitErr *qparam.IndexLimitError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, "ids[6]", limitErr.Param)
+		assert.Equal(t, 6, limitErr.Index)
+		assert.Equal(t, 5, limitErr.Max)
+	})
+
+	t.Run("alias conflict error", func(t *testing.T) {
+		target := struct {
+			Limit int `param:"limit,alias=per_page"`
+		}{}
+		reader := qparam.NewReader(qparam.AliasConflicts(true))
+		err := reader.Read(url.Values{"limit": []string{"1"}, "per_page": []string{"2"}}, &target)
+		require.Error(t, err)
+
+		var conflictErr *qparam.AliasConflictError
+		require.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, "limit", conflictErr.Param)
+		assert.Equal(t, []string{"limit", "per_page"}, conflictErr.Names)
+		assert.Equal(t, `conflicting parameters ["limit" "per_page"] for the same field`, conflictErr.Error())
+	})
+
+	t.Run("constraint error", func(t *testing.T) {
+		target := struct {
+			Limit int `min:"1"`
+		}{}
+		err := qparam.NewReader().Read(url.Values{"limit": []string{"0"}}, &target)
+		require.Error(t, err)
+
+		var constraintErr *qparam.ConstraintError
+		require.True(t, errors.As(err, &constraintErr))
+		assert.Equal(t, "limit", constraintErr.Param)
+		assert.Equal(t, "0", constraintErr.Value)
+		assert.Equal(t, "min", constraintErr.Constraint)
+		assert.Equal(t, "1", constraintErr.Limit)
+		assert.Equal(t, `value "0" violates constraint min:"1"`, constraintErr.Error())
+	})
+
+	t.Run("multi error", func(t *testing.T) {
+		var parseErr *qparam.ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, "numbers", parseErr.Param, "errors must be unwrapped in order of their names")
+
+		var unknownErr *qparam.UnknownParameterError
+		assert.True(t, errors.As(err, &unknownErr))
+	})
+}
@@ -1,6 +1,6 @@
 module github.com/stoewer/go-qparam
 
-go 1.11
+go 1.20
 
 require (
 	github.com/pkg/errors v0.8.1
@@ -8,3 +8,9 @@ require (
 	github.com/stretchr/testify v1.4.0
 	gopkg.in/guregu/null.v3 v3.4.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
@@ -0,0 +1,104 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Delimiters maps the names of collection formats to the delimiters used by them. The format
+// multi uses repeated parameters instead of a delimiter.
+var Delimiters = map[string]string{
+	"csv":   ",",
+	"ssv":   " ",
+	"tsv":   "\t",
+	"pipes": "|",
+	"multi": "",
+}
+
+// Split splits each of the provided values into elements separated by the delimiter. Elements
+// enclosed in double quotes may contain the delimiter, a double quote inside such an element
+// is escaped by a second double quote. If the delimiter is empty, the values are returned as
+// they are.
+func Split(values []string, delim string) ([]string, error) {
+	if delim == "" {
+		return values, nil
+	}
+
+	elements := make([]string, 0, len(values))
+	for _, value := range values {
+		for more := true; more; {
+			var elem string
+			var err error
+
+			elem, value, more, err = splitFirst(value, delim)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, elem)
+		}
+	}
+
+	return elements, nil
+}
+
+// splitFirst returns the first element of the value and the remainder after the delimiter. The
+// third returned value is false if the value contains no further delimiter.
+func splitFirst(value, delim string) (string, string, bool, error) {
+	if !strings.HasPrefix(value, `"`) {
+		i := strings.Index(value, delim)
+		if i < 0 {
+			return value, "", false, nil
+		}
+		return value[:i], value[i+len(delim):], true, nil
+	}
+
+	var builder strings.Builder
+	for i := 1; i < len(value); i++ {
+		if value[i] != '"' {
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		// escaped double quote
+		if i+1 < len(value) && value[i+1] == '"' {
+			builder.WriteByte('"')
+			i++
+			continue
+		}
+
+		// closing double quote
+		rest := value[i+1:]
+		if rest == "" {
+			return builder.String(), "", false, nil
+		}
+		if !strings.HasPrefix(rest, delim) {
+			return "", "", false, errors.Errorf("unexpected characters after quoted element in %q", value)
+		}
+		return builder.String(), rest[len(delim):], true, nil
+	}
+
+	return "", "", false, errors.Errorf("missing closing double quote in %q", value)
+}
+
+// Join joins the provided elements using the delimiter. Elements are quoted if necessary, such
+// that Split restores the original elements. If the delimiter is empty, the elements are returned
+// as they are.
+func Join(elements []string, delim string) []string {
+	if delim == "" {
+		return elements
+	}
+
+	quoted := make([]string, len(elements))
+	for i, elem := range elements {
+		if strings.Contains(elem, delim) || strings.HasPrefix(elem, `"`) {
+			elem = `"` + strings.Replace(elem, `"`, `""`, -1) + `"`
+		}
+		quoted[i] = elem
+	}
+
+	return []string{strings.Join(quoted, delim)}
+}
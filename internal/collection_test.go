@@ -0,0 +1,70 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stoewer/go-qparam/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	data := []struct {
+		Name        string
+		Values      []string
+		Delimiter   string
+		Expected    []string
+		ExpectedErr bool
+	}{
+		{Name: "no delimiter", Values: []string{"a,b", "c"}, Delimiter: "", Expected: []string{"a,b", "c"}},
+		{Name: "csv", Values: []string{"a,b", "c"}, Delimiter: ",", Expected: []string{"a", "b", "c"}},
+		{Name: "empty elements", Values: []string{",a,"}, Delimiter: ",", Expected: []string{"", "a", ""}},
+		{Name: "pipes", Values: []string{"a|b|c"}, Delimiter: "|", Expected: []string{"a", "b", "c"}},
+		{Name: "ssv", Values: []string{"a b"}, Delimiter: " ", Expected: []string{"a", "b"}},
+		{Name: "quoted", Values: []string{`"a,b",c`}, Delimiter: ",", Expected: []string{"a,b", "c"}},
+		{Name: "escaped quotes", Values: []string{`"a ""b""",c`}, Delimiter: ",", Expected: []string{`a "b"`, "c"}},
+		{Name: "inner quotes", Values: []string{`a"b,c"`}, Delimiter: ",", Expected: []string{`a"b`, `c"`}},
+		{Name: "quoted last", Values: []string{`a,"b|c"`}, Delimiter: ",", Expected: []string{"a", "b|c"}},
+		{Name: "missing quote", Values: []string{`a,"b`}, Delimiter: ",", ExpectedErr: true},
+		{Name: "characters after quote", Values: []string{`"a"b,c`}, Delimiter: ",", ExpectedErr: true},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.Name, func(t *testing.T) {
+			elements, err := internal.Split(tt.Values, tt.Delimiter)
+			if tt.ExpectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.Expected, elements)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	data := []struct {
+		Name      string
+		Elements  []string
+		Delimiter string
+		Expected  []string
+	}{
+		{Name: "no delimiter", Elements: []string{"a,b", "c"}, Delimiter: "", Expected: []string{"a,b", "c"}},
+		{Name: "csv", Elements: []string{"a", "b", "c"}, Delimiter: ",", Expected: []string{"a,b,c"}},
+		{Name: "quoted", Elements: []string{"a,b", "c"}, Delimiter: ",", Expected: []string{`"a,b",c`}},
+		{Name: "leading quote", Elements: []string{`"a"`, `b"`}, Delimiter: "|", Expected: []string{`"""a"""|b"`}},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.Name, func(t *testing.T) {
+			joined := internal.Join(tt.Elements, tt.Delimiter)
+			assert.Equal(t, tt.Expected, joined)
+
+			split, err := internal.Split(joined, tt.Delimiter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.Elements, split)
+		})
+	}
+}
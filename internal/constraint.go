@@ -0,0 +1,166 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// constraintTags are the struct tags which declare constraints for single values, in the order in
+// which the constraints are checked.
+var constraintTags = []string{"min", "max", "minlen", "maxlen", "oneof", "pattern"}
+
+// Constraint is a condition which each parsed value (or slice element) of a field must satisfy.
+// Constraints are declared by struct tags, e.g. `min:"1"` or `oneof:"asc|desc"`.
+type Constraint struct {
+	// Name is the struct tag which declares the constraint, e.g. "min".
+	Name string
+	// Limit is the value of the struct tag, e.g. "1".
+	Limit string
+	check func(value reflect.Value, raw string) bool
+}
+
+// Check returns true if the parsed value satisfies the constraint. The raw value is the string from
+// which the value was parsed.
+func (c *Constraint) Check(value reflect.Value, raw string) bool {
+	return c.check(value, raw)
+}
+
+// compileConstraints creates the constraints declared by the tags of a field. The limits of the
+// constraints min, max and oneof are parsed using the parser of the field.
+func compileConstraints(tag reflect.StructTag, parser Parser, elem reflect.Type) ([]Constraint, error) {
+	var constraints []Constraint
+	for _, name := range constraintTags {
+		limit, ok := tag.Lookup(name)
+		if !ok {
+			continue
+		}
+
+		if parser == nil {
+			return nil, errors.Errorf("constraint %s: field type is not supported", name)
+		}
+
+		check, err := compileCheck(name, limit, parser, elem)
+		if err != nil {
+			return nil, errors.Wrapf(err, "constraint %s", name)
+		}
+		constraints = append(constraints, Constraint{Name: name, Limit: limit, check: check})
+	}
+
+	return constraints, nil
+}
+
+func compileCheck(name, limit string, parser Parser, elem reflect.Type) (func(reflect.Value, string) bool, error) {
+	switch name {
+	case "min", "max":
+		bound := reflect.New(elem).Elem()
+		err := parser.Parse(bound, limit)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := compare(bound, bound); !ok {
+			return nil, errors.New("only numbers are supported")
+		}
+
+		if name == "min" {
+			return func(value reflect.Value, _ string) bool {
+				cmp, ok := compare(value, bound)
+				return ok && cmp >= 0
+			}, nil
+		}
+		return func(value reflect.Value, _ string) bool {
+			cmp, ok := compare(value, bound)
+			return ok && cmp <= 0
+		}, nil
+
+	case "minlen", "maxlen":
+		length, err := strconv.Atoi(limit)
+		if err != nil || length < 0 {
+			return nil, errors.Errorf("invalid length %q", limit)
+		}
+		if elem.Kind() != reflect.String {
+			return nil, errors.New("only strings are supported")
+		}
+
+		if name == "minlen" {
+			return func(value reflect.Value, _ string) bool {
+				return utf8.RuneCountInString(value.String()) >= length
+			}, nil
+		}
+		return func(value reflect.Value, _ string) bool {
+			return utf8.RuneCountInString(value.String()) <= length
+		}, nil
+
+	case "oneof":
+		var options []interface{}
+		for _, option := range strings.Split(limit, "|") {
+			parsed := reflect.New(elem).Elem()
+			err := parser.Parse(parsed, option)
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, parsed.Interface())
+		}
+
+		return func(value reflect.Value, _ string) bool {
+			for _, option := range options {
+				if reflect.DeepEqual(value.Interface(), option) {
+					return true
+				}
+			}
+			return false
+		}, nil
+
+	case "pattern":
+		pattern, err := regexp.Compile(limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(_ reflect.Value, raw string) bool {
+			return pattern.MatchString(raw)
+		}, nil
+	}
+
+	return nil, errors.Errorf("unknown constraint %s", name)
+}
+
+// compare compares two numbers of the same kind. The second returned value is false if the values
+// are no numbers or can't be compared (NaN).
+func compare(a, b reflect.Value) (int, bool) {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, y := a.Int(), b.Int()
+		if x < y {
+			return -1, true
+		} else if x > y {
+			return 1, true
+		}
+		return 0, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, y := a.Uint(), b.Uint()
+		if x < y {
+			return -1, true
+		} else if x > y {
+			return 1, true
+		}
+		return 0, true
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		if x < y {
+			return -1, true
+		} else if x > y {
+			return 1, true
+		} else if x == y {
+			return 0, true
+		}
+	}
+	return 0, false
+}
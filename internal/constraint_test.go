@@ -0,0 +1,134 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stoewer/go-qparam/internal"
+	"github.com/stoewer/go-strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraint_Check(t *testing.T) {
+	type order string
+
+	type constrained struct {
+		Int     int     `min:"-5" max:"10"`
+		Uint    uint8   `max:"200"`
+		Float   float64 `min:"0.5"`
+		Name    string  `minlen:"2" maxlen:"3"`
+		Order   order   `oneof:"asc|desc"`
+		Numbers []int   `oneof:"1|2|3"`
+		Code    string  `pattern:"^[A-Z]{2}$"`
+	}
+
+	tests := []struct {
+		field string
+		value interface{}
+		raw   string
+		valid bool
+	}{
+		{"int", -5, "-5", true},
+		{"int", -6, "-6", false},
+		{"int", 10, "10", true},
+		{"int", 11, "11", false},
+		{"uint", uint8(200), "200", true},
+		{"uint", uint8(201), "201", false},
+		{"float", 0.5, "0.5", true},
+		{"float", 0.49, "0.49", false},
+		{"float", math.NaN(), "NaN", false},
+		{"name", "ab", "ab", true},
+		{"name", "äöü", "äöü", true},
+		{"name", "a", "a", false},
+		{"name", "abcd", "abcd", false},
+		{"order", order("asc"), "asc", true},
+		{"order", order("up"), "up", false},
+		{"numbers", 2, "02", true},
+		{"numbers", 4, "4", false},
+		{"code", "DE", "DE", true},
+		{"code", "DEU", "DEU", false},
+	}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+	plan, err := internal.Compile(reflect.TypeOf(constrained{}), config)
+	require.NoError(t, err)
+
+	byPath := make(map[string]internal.Field)
+	for _, field := range plan.Fields {
+		byPath[field.Path] = field
+	}
+
+	for _, test := range tests {
+		field := byPath[test.field]
+		require.NotEmpty(t, field.Constraints)
+
+		valid := true
+		for _, constraint := range field.Constraints {
+			valid = valid && constraint.Check(reflect.ValueOf(test.value), test.raw)
+		}
+		assert.Equal(t, test.valid, valid, "field %s with value %s", test.field, test.raw)
+	}
+}
+
+func TestCompile_Constraints(t *testing.T) {
+	config := &internal.Config{Tag: "param", DefaultTag: "default", Mapper: strcase.SnakeCase, Syntax: dot}
+
+	t.Run("valid", func(t *testing.T) {
+		type constrained struct {
+			Limit int                     `min:"1" default:"10"`
+			IDs   []int                   `maxitems:"5"`
+			Items []struct{ Name string } `maxitems:"5"`
+		}
+
+		plan, err := internal.Compile(reflect.TypeOf(constrained{}), config)
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(plan.Fields[0].Constraints))
+		assert.Equal(t, 5, plan.Fields[1].MaxItems)
+		assert.Equal(t, 5, plan.Fields[2].MaxItems)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		targets := []interface{}{
+			struct {
+				Name string `min:"1"`
+			}{},
+			struct {
+				Limit int `min:"one"`
+			}{},
+			struct {
+				Limit int `minlen:"1"`
+			}{},
+			struct {
+				Name string `maxlen:"-1"`
+			}{},
+			struct {
+				Limit int `oneof:"1|two"`
+			}{},
+			struct {
+				Name string `pattern:"[a-z"`
+			}{},
+			struct {
+				Name string `maxitems:"5"`
+			}{},
+			struct {
+				IDs []int `maxitems:"0"`
+			}{},
+			struct {
+				Limit int `min:"1" default:"0"`
+			}{},
+			struct {
+				Nested struct{ Name string } `oneof:"a|b"`
+			}{},
+		}
+
+		for _, target := range targets {
+			_, err := internal.Compile(reflect.TypeOf(target), config)
+			assert.Error(t, err, "type %T", target)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal
+
+import (
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// fileTags are the struct tags which limit the uploaded files of a field.
+var fileTags = []string{"maxsize", "accept"}
+
+var (
+	fileHeaderType      = reflect.TypeOf(multipart.FileHeader{})
+	fileHeaderPtrType   = reflect.PtrTo(fileHeaderType)
+	fileHeaderSliceType = reflect.SliceOf(fileHeaderPtrType)
+)
+
+// compileFile configures a field for uploaded files. Such fields must have the type *multipart.FileHeader
+// or []*multipart.FileHeader. The size of each file can be limited by the tag "maxsize" (in bytes), the
+// accepted content types by the tag "accept", e.g. `accept:"image/png|image/*"`.
+func compileFile(field *Field) error {
+	if field.Source != "" || (field.Type != fileHeaderPtrType && field.Type != fileHeaderSliceType) {
+		return errors.New("only *multipart.FileHeader and []*multipart.FileHeader are supported")
+	}
+
+	if field.Delimiter != "" {
+		return errors.New("collection formats are not supported")
+	}
+
+	field.File = true
+
+	if size, ok := field.Tag.Lookup("maxsize"); ok {
+		var err error
+		field.MaxSize, err = strconv.ParseInt(size, 10, 64)
+		if err != nil || field.MaxSize <= 0 {
+			return errors.New("maxsize must be a positive number of bytes")
+		}
+	}
+
+	if accept, ok := field.Tag.Lookup("accept"); ok {
+		for _, contentType := range strings.Split(accept, "|") {
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err != nil || !strings.Contains(mediaType, "/") {
+				return errors.Errorf("invalid content type %q", contentType)
+			}
+			field.Accept = append(field.Accept, mediaType)
+		}
+	}
+
+	return nil
+}
+
+// Accepts returns true if the content type of an uploaded file matches one of the accepted content types
+// of the field. Accepted types like "image/*" match all subtypes. All content types are accepted if the
+// field has no accepted content types.
+func (f *Field) Accepts(contentType string) bool {
+	if len(f.Accept) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, accepted := range f.Accept {
+		if accepted == mediaType {
+			return true
+		}
+		if prefix := strings.TrimSuffix(accepted, "*"); prefix != accepted && strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal_test
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+
+	"github.com/stoewer/go-qparam/internal"
+	"github.com/stoewer/go-strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile_File(t *testing.T) {
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot, Collection: "csv",
+		DefaultTag: "default", Sources: []internal.NamedSource{{Name: "header", Tag: "header"}}}
+
+	type upload struct {
+		Avatar      *multipart.FileHeader `maxsize:"1024" accept:"image/png|image/*"`
+		Attachments []*multipart.FileHeader
+	}
+
+	plan, err := internal.Compile(reflect.TypeOf(upload{}), config)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(plan.Fields))
+
+	avatar := plan.Fields[0]
+	assert.Equal(t, "avatar", avatar.Path)
+	assert.True(t, avatar.File)
+	assert.False(t, avatar.Struct)
+	assert.Equal(t, int64(1024), avatar.MaxSize)
+	assert.Equal(t, []string{"image/png", "image/*"}, avatar.Accept)
+
+	attachments := plan.Fields[1]
+	assert.Equal(t, "attachments", attachments.Path)
+	assert.True(t, attachments.File)
+	assert.True(t, attachments.Slice)
+	assert.Nil(t, attachments.Elem)
+	assert.Equal(t, "", attachments.Delimiter)
+
+	invalid := []interface{}{
+		struct {
+			File multipart.FileHeader
+		}{},
+		struct {
+			Files map[string]*multipart.FileHeader
+		}{},
+		struct {
+			File *multipart.FileHeader `header:"X-File"`
+		}{},
+		struct {
+			File *multipart.FileHeader `maxsize:"1MB"`
+		}{},
+		struct {
+			File *multipart.FileHeader `accept:"image"`
+		}{},
+		struct {
+			File *multipart.FileHeader `default:"file.txt"`
+		}{},
+		struct {
+			Name string `maxsize:"10"`
+		}{},
+		struct {
+			Files []*multipart.FileHeader `param:",csv"`
+		}{},
+	}
+
+	for _, target := range invalid {
+		_, err = internal.Compile(reflect.TypeOf(target), config)
+		assert.Error(t, err, "%T", target)
+	}
+}
+
+func TestField_Accepts(t *testing.T) {
+	field := internal.Field{Accept: []string{"image/png", "text/*"}}
+
+	assert.True(t, field.Accepts("image/png"))
+	assert.True(t, field.Accepts("IMAGE/PNG"))
+	assert.True(t, field.Accepts("text/plain; charset=utf-8"))
+	assert.False(t, field.Accepts("image/jpeg"))
+	assert.False(t, field.Accepts("application/text"))
+	assert.False(t, field.Accepts(""))
+
+	field = internal.Field{}
+	assert.True(t, field.Accepts(""))
+}
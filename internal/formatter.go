@@ -0,0 +1,115 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Formatter is used to convert the provided value into its string representation.
+type Formatter interface {
+	Format(reflect.Value) (string, error)
+}
+
+// CheckedFormatter is a formatter that has a method (Check) which can be used to determine whether the
+// formatter can be applied to values of a certain type. It is recommended to call Check prior to calling Format.
+type CheckedFormatter interface {
+	Formatter
+	Check(reflect.Type) bool
+}
+
+var registeredFormatters = map[reflect.Kind]Formatter{
+	reflect.Int:     intFormatter,
+	reflect.Int8:    intFormatter,
+	reflect.Int16:   intFormatter,
+	reflect.Int32:   intFormatter,
+	reflect.Int64:   intFormatter,
+	reflect.Uint:    uintFormatter,
+	reflect.Uint8:   uintFormatter,
+	reflect.Uint16:  uintFormatter,
+	reflect.Uint32:  uintFormatter,
+	reflect.Uint64:  uintFormatter,
+	reflect.Float32: float32Formatter,
+	reflect.Float64: float64Formatter,
+	reflect.Bool:    boolFormatter,
+	reflect.String:  stringFormatter,
+}
+
+var registeredCheckedFormatters = []CheckedFormatter{
+	textFormatter{},
+	durationFormatter{},
+}
+
+// FindFormatter finds a Formatter that matches the provided type. If such a formatter
+// was found the second returned value will be true, it is false otherwise.
+func FindFormatter(t reflect.Type) (Formatter, bool) {
+	for _, formatter := range registeredCheckedFormatters {
+		if formatter.Check(t) {
+			return formatter, true
+		}
+	}
+
+	formatter, ok := registeredFormatters[t.Kind()]
+	return formatter, ok
+}
+
+type formatterFunc func(reflect.Value) (string, error)
+
+func (fn formatterFunc) Format(value reflect.Value) (string, error) {
+	return fn(value)
+}
+
+var intFormatter = formatterFunc(func(value reflect.Value) (string, error) {
+	return strconv.FormatInt(value.Int(), 10), nil
+})
+
+var uintFormatter = formatterFunc(func(value reflect.Value) (string, error) {
+	return strconv.FormatUint(value.Uint(), 10), nil
+})
+
+var float32Formatter = formatterFunc(func(value reflect.Value) (string, error) {
+	return strconv.FormatFloat(value.Float(), 'g', -1, 32), nil
+})
+
+var float64Formatter = formatterFunc(func(value reflect.Value) (string, error) {
+	return strconv.FormatFloat(value.Float(), 'g', -1, 64), nil
+})
+
+var boolFormatter = formatterFunc(func(value reflect.Value) (string, error) {
+	return strconv.FormatBool(value.Bool()), nil
+})
+
+var stringFormatter = formatterFunc(func(value reflect.Value) (string, error) {
+	return value.String(), nil
+})
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+type textFormatter struct{}
+
+func (f textFormatter) Check(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+func (f textFormatter) Format(value reflect.Value) (string, error) {
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		value = value.Addr()
+	}
+
+	marshaler, ok := value.Interface().(encoding.TextMarshaler)
+	if !ok || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return "", errors.New("method MarshalText not available")
+	}
+
+	b, err := marshaler.MarshalText()
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stoewer/go-qparam/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindFormatter(t *testing.T) {
+	data := []struct {
+		Name     string
+		Value    reflect.Value
+		Expected string
+	}{
+		{Name: "int", Value: reflect.ValueOf(int(-6129484611666145821)), Expected: "-6129484611666145821"},
+		{Name: "int8", Value: reflect.ValueOf(int8(-128)), Expected: "-128"},
+		{Name: "int16", Value: reflect.ValueOf(int16(32767)), Expected: "32767"},
+		{Name: "int32", Value: reflect.ValueOf(int32(-42)), Expected: "-42"},
+		{Name: "int64", Value: reflect.ValueOf(int64(8674665223082153551)), Expected: "8674665223082153551"},
+		{Name: "uint", Value: reflect.ValueOf(uint(42)), Expected: "42"},
+		{Name: "uint8", Value: reflect.ValueOf(uint8(255)), Expected: "255"},
+		{Name: "uint16", Value: reflect.ValueOf(uint16(65535)), Expected: "65535"},
+		{Name: "uint32", Value: reflect.ValueOf(uint32(7)), Expected: "7"},
+		{Name: "uint64", Value: reflect.ValueOf(uint64(18446744073709551615)), Expected: "18446744073709551615"},
+		{Name: "float32", Value: reflect.ValueOf(float32(0.1)), Expected: "0.1"},
+		{Name: "float64", Value: reflect.ValueOf(float64(-1.5e-10)), Expected: "-1.5e-10"},
+		{Name: "bool", Value: reflect.ValueOf(true), Expected: "true"},
+		{Name: "string", Value: reflect.ValueOf("foo"), Expected: "foo"},
+		{Name: "text marshaler", Value: reflect.ValueOf(now), Expected: nowStr},
+		{Name: "text marshaler pointer", Value: reflect.ValueOf(&nullTrue), Expected: "true"},
+		{Name: "addressable text marshaler", Value: reflect.ValueOf(&nullTrue).Elem(), Expected: "true"},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.Name, func(t *testing.T) {
+			formatter, ok := internal.FindFormatter(tt.Value.Type())
+			require.True(t, ok, "no formatter found")
+
+			s, err := formatter.Format(tt.Value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.Expected, s)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, ok := internal.FindFormatter(reflect.TypeOf(map[string]string{}))
+		assert.False(t, ok)
+	})
+
+	t.Run("nil text marshaler", func(t *testing.T) {
+		formatter, ok := internal.FindFormatter(reflect.TypeOf((*time.Time)(nil)))
+		require.True(t, ok, "no formatter found")
+
+		_, err := formatter.Format(reflect.ValueOf((*time.Time)(nil)))
+		assert.Error(t, err)
+	})
+}
@@ -4,8 +4,10 @@
 package internal
 
 import (
+	"encoding"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -16,10 +18,10 @@ type Parser interface {
 }
 
 // CheckedParser is a parser that has a method (Check) which can be used to determine whether the
-// parser can be applied to a certain value. It is recommended to call Check prior to calling Parse.
+// parser can be applied to values of a certain type. It is recommended to call Check prior to calling Parse.
 type CheckedParser interface {
 	Parser
-	Check(reflect.Value) bool
+	Check(reflect.Type) bool
 }
 
 var registeredParsers = map[reflect.Kind]Parser{
@@ -41,21 +43,67 @@ var registeredParsers = map[reflect.Kind]Parser{
 
 var registeredCheckedParsers = []CheckedParser{
 	textParser{},
+	durationParser{},
 }
 
-// FindParser finds a Parser that matches the provided value. If such a parser
+// FindParser finds a Parser that matches the provided type. If such a parser
 // was found the second returned value will be true, it is false otherwise.
-func FindParser(value reflect.Value) (Parser, bool) {
+func FindParser(t reflect.Type) (Parser, bool) {
 	for _, parser := range registeredCheckedParsers {
-		if parser.Check(value) {
+		if parser.Check(t) {
 			return parser, true
 		}
 	}
 
-	parser, ok := registeredParsers[value.Kind()]
+	parser, ok := registeredParsers[t.Kind()]
 	return parser, ok
 }
 
+// FuncParser is a CheckedParser which delegates to the provided functions: Match is used to check
+// whether the parser can be applied to a type, and Func parses a string into a value which must be
+// assignable or convertible to the checked type.
+type FuncParser struct {
+	Match func(reflect.Type) bool
+	Func  func(reflect.Type, string) (interface{}, error)
+}
+
+// Check calls the parsers Match function.
+func (p FuncParser) Check(t reflect.Type) bool {
+	return p.Match(t)
+}
+
+// Parse calls the parsers Func and assigns the result to the provided value.
+func (p FuncParser) Parse(value reflect.Value, s string) error {
+	result, err := p.Func(value.Type(), s)
+	if err != nil {
+		return err
+	}
+
+	resultVal := reflect.ValueOf(result)
+	switch {
+	case !resultVal.IsValid():
+		value.Set(reflect.Zero(value.Type()))
+	case resultVal.Type().AssignableTo(value.Type()):
+		value.Set(resultVal)
+	case resultVal.Type().ConvertibleTo(value.Type()) && convertible(resultVal.Kind(), value.Kind()):
+		value.Set(resultVal.Convert(value.Type()))
+	default:
+		return errors.Errorf("parser result of type %s can't be assigned to %s", resultVal.Type(), value.Type())
+	}
+
+	return nil
+}
+
+// convertible restricts conversions to values of the same kind or between numbers,
+// e.g. conversions from int to string are not desired.
+func convertible(from, to reflect.Kind) bool {
+	return from == to || (isNumber(from) && isNumber(to))
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
 type parserFunc func(reflect.Value, string) error
 
 func (fn parserFunc) Parse(value reflect.Value, s string) error {
@@ -67,7 +115,7 @@ var intParser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(int(i)))
+	value.SetInt(i)
 	return nil
 })
 
@@ -76,7 +124,7 @@ var int8Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(int8(i)))
+	value.SetInt(i)
 	return nil
 })
 
@@ -85,7 +133,7 @@ var int16Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(int16(i)))
+	value.SetInt(i)
 	return nil
 })
 
@@ -94,7 +142,7 @@ var int32Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(int32(i)))
+	value.SetInt(i)
 	return nil
 })
 
@@ -103,7 +151,7 @@ var int64Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(i))
+	value.SetInt(i)
 	return nil
 })
 
@@ -112,7 +160,7 @@ var uintParser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(uint(i)))
+	value.SetUint(i)
 	return nil
 })
 
@@ -121,7 +169,7 @@ var uint8Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(uint8(i)))
+	value.SetUint(i)
 	return nil
 })
 
@@ -130,7 +178,7 @@ var uint16Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(uint16(i)))
+	value.SetUint(i)
 	return nil
 })
 
@@ -139,7 +187,7 @@ var uint32Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(uint32(i)))
+	value.SetUint(i)
 	return nil
 })
 
@@ -148,7 +196,7 @@ var uint64Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(i))
+	value.SetUint(i)
 	return nil
 })
 
@@ -157,7 +205,7 @@ var float32Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(float32(f)))
+	value.SetFloat(f)
 	return nil
 })
 
@@ -166,7 +214,7 @@ var float64Parser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(f))
+	value.SetFloat(f)
 	return nil
 })
 
@@ -175,45 +223,61 @@ var boolParser = parserFunc(func(value reflect.Value, s string) error {
 	if err != nil {
 		return err
 	}
-	value.Set(reflect.ValueOf(b))
+	value.SetBool(b)
 	return nil
 })
 
+// lenientBoolParser parses bools like strconv.ParseBool, but accepts also the words yes/no, y/n and on/off
+// in any case. If presence is true, empty values (e.g. the flag "?verbose") are parsed as true.
+type lenientBoolParser struct {
+	presence bool
+}
+
+func (p lenientBoolParser) Parse(value reflect.Value, s string) error {
+	if s == "" && p.presence {
+		value.SetBool(true)
+		return nil
+	}
+
+	switch s = strings.ToLower(s); s {
+	case "yes", "y", "on":
+		value.SetBool(true)
+		return nil
+	case "no", "n", "off":
+		value.SetBool(false)
+		return nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	value.SetBool(b)
+	return nil
+}
+
 var stringParser = parserFunc(func(value reflect.Value, s string) error {
-	value.Set(reflect.ValueOf(s))
+	value.SetString(s)
 	return nil
 })
 
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
 type textParser struct{}
 
-func (p textParser) Check(value reflect.Value) bool {
-	return p.method(value).IsValid()
+func (p textParser) Check(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType)
 }
 
 func (p textParser) Parse(value reflect.Value, s string) error {
-	unmarshal := p.method(value)
-	if !unmarshal.IsValid() {
-		return errors.New("method UnmarshalText not available")
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		value = value.Addr()
 	}
 
-	returned := unmarshal.Call([]reflect.Value{reflect.ValueOf([]byte(s))})
-	if len(returned) > 0 && !returned[0].IsNil() {
-		return errors.Errorf("%s", returned[0])
+	unmarshaler, ok := value.Interface().(encoding.TextUnmarshaler)
+	if !ok || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return errors.New("method UnmarshalText not available")
 	}
 
-	return nil
-}
-
-func (p textParser) method(value reflect.Value) reflect.Value {
-	m := value.MethodByName("UnmarshalText")
-	if !m.IsValid() {
-		if value.Kind() == reflect.Ptr {
-			m = value.Elem().MethodByName("UnmarshalText")
-		} else {
-			if value.CanAddr() {
-				m = value.Addr().MethodByName("UnmarshalText")
-			}
-		}
-	}
-	return m
+	return unmarshaler.UnmarshalText([]byte(s))
 }
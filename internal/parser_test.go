@@ -4,6 +4,7 @@
 package internal_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -155,7 +156,7 @@ func TestSelectParser_Int(t *testing.T) {
 
 	for _, tt := range data {
 		t.Run(tt.Name, func(t *testing.T) {
-			parser, ok := internal.FindParser(tt.Expected)
+			parser, ok := internal.FindParser(tt.Expected.Type())
 			require.True(t, ok)
 
 			err := parser.Parse(tt.Target.Elem(), tt.Value)
@@ -281,7 +282,7 @@ func TestSelectParser_Uint(t *testing.T) {
 
 	for _, tt := range data {
 		t.Run(tt.Name, func(t *testing.T) {
-			parser, ok := internal.FindParser(tt.Expected)
+			parser, ok := internal.FindParser(tt.Expected.Type())
 			require.True(t, ok)
 
 			err := parser.Parse(tt.Target.Elem(), tt.Value)
@@ -347,7 +348,7 @@ func TestSelectParser_Float(t *testing.T) {
 
 	for _, tt := range data {
 		t.Run(tt.Name, func(t *testing.T) {
-			parser, ok := internal.FindParser(tt.Expected)
+			parser, ok := internal.FindParser(tt.Expected.Type())
 			require.True(t, ok)
 
 			err := parser.Parse(tt.Target.Elem(), tt.Value)
@@ -392,7 +393,7 @@ func TestSelectParser_Bool(t *testing.T) {
 
 	for _, tt := range data {
 		t.Run(tt.Name, func(t *testing.T) {
-			parser, ok := internal.FindParser(tt.Expected)
+			parser, ok := internal.FindParser(tt.Expected.Type())
 			require.True(t, ok)
 
 			err := parser.Parse(tt.Target.Elem(), tt.Value)
@@ -406,6 +407,54 @@ func TestSelectParser_Bool(t *testing.T) {
 	}
 }
 
+func TestConfig_FindParser_Bool(t *testing.T) {
+	data := []struct {
+		Name     string
+		Config   internal.Config
+		Value    string
+		Expected bool
+		Err      bool
+	}{
+		{Name: "strict true", Config: internal.Config{}, Value: "True", Expected: true},
+		{Name: "strict on", Config: internal.Config{}, Value: "on", Err: true},
+		{Name: "strict empty", Config: internal.Config{}, Value: "", Err: true},
+		{Name: "lenient on", Config: internal.Config{LenientBools: true}, Value: "on", Expected: true},
+		{Name: "lenient yes", Config: internal.Config{LenientBools: true}, Value: "YES", Expected: true},
+		{Name: "lenient n", Config: internal.Config{LenientBools: true}, Value: "n", Expected: false},
+		{Name: "lenient off", Config: internal.Config{LenientBools: true}, Value: "Off", Expected: false},
+		{Name: "lenient 0", Config: internal.Config{LenientBools: true}, Value: "0", Expected: false},
+		{Name: "lenient empty", Config: internal.Config{LenientBools: true}, Value: "", Err: true},
+		{Name: "lenient invalid", Config: internal.Config{LenientBools: true}, Value: "maybe", Err: true},
+		{Name: "presence empty", Config: internal.Config{PresenceBools: true}, Value: "", Expected: true},
+		{Name: "presence no", Config: internal.Config{PresenceBools: true}, Value: "no", Expected: false},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.Name, func(t *testing.T) {
+			parser, ok := tt.Config.FindParser(reflect.TypeOf(false))
+			require.True(t, ok)
+
+			target := !tt.Expected
+			err := parser.Parse(reflect.ValueOf(&target).Elem(), tt.Value)
+			if tt.Err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.Expected, target)
+			}
+		})
+	}
+
+	t.Run("text unmarshaler", func(t *testing.T) {
+		config := internal.Config{PresenceBools: true}
+		parser, ok := config.FindParser(reflect.TypeOf(null.Bool{}))
+		require.True(t, ok)
+
+		target := null.Bool{}
+		assert.Error(t, parser.Parse(reflect.ValueOf(&target).Elem(), "on"))
+	})
+}
+
 func TestSelectParser_String(t *testing.T) {
 	data := []struct {
 		Name        string
@@ -420,7 +469,7 @@ func TestSelectParser_String(t *testing.T) {
 
 	for _, tt := range data {
 		t.Run(tt.Name, func(t *testing.T) {
-			parser, ok := internal.FindParser(tt.Expected)
+			parser, ok := internal.FindParser(tt.Expected.Type())
 			require.True(t, ok)
 
 			err := parser.Parse(tt.Target.Elem(), tt.Value)
@@ -434,6 +483,32 @@ func TestSelectParser_String(t *testing.T) {
 	}
 }
 
+func TestSelectParser_NamedTypes(t *testing.T) {
+	type order string
+	type level uint8
+	type ratio float32
+
+	data := []struct {
+		Value    string
+		Target   interface{}
+		Expected interface{}
+	}{
+		{Value: "asc", Target: new(order), Expected: order("asc")},
+		{Value: "3", Target: new(level), Expected: level(3)},
+		{Value: "0.5", Target: new(ratio), Expected: ratio(0.5)},
+	}
+
+	for _, tt := range data {
+		target := reflect.ValueOf(tt.Target).Elem()
+		parser, ok := internal.FindParser(target.Type())
+		require.True(t, ok, "no parser found")
+
+		err := parser.Parse(target, tt.Value)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Expected, target.Interface())
+	}
+}
+
 func TestSelectParser_TextUnmarshaler(t *testing.T) {
 	data := []struct {
 		Value       string
@@ -449,7 +524,7 @@ func TestSelectParser_TextUnmarshaler(t *testing.T) {
 	}
 
 	for _, tt := range data {
-		parser, ok := internal.FindParser(reflect.ValueOf(tt.Target))
+		parser, ok := internal.FindParser(reflect.TypeOf(tt.Target))
 		require.True(t, ok, "no parser found")
 
 		err := parser.Parse(reflect.ValueOf(tt.Target), tt.Value)
@@ -461,3 +536,35 @@ func TestSelectParser_TextUnmarshaler(t *testing.T) {
 		}
 	}
 }
+
+func TestFuncParser(t *testing.T) {
+	type status int
+
+	parser := internal.FuncParser{
+		Match: func(t reflect.Type) bool { return t.Kind() == reflect.Int },
+		Func: func(t reflect.Type, s string) (interface{}, error) {
+			switch s {
+			case "nil":
+				return nil, nil
+			case "int64":
+				return int64(42), nil
+			case "string":
+				return "42", nil
+			}
+			return nil, errors.New("invalid value")
+		},
+	}
+
+	assert.True(t, parser.Check(reflect.TypeOf(status(0))))
+	assert.False(t, parser.Check(reflect.TypeOf("")))
+
+	target := status(7)
+	assert.NoError(t, parser.Parse(reflect.ValueOf(&target).Elem(), "int64"))
+	assert.Equal(t, status(42), target)
+
+	assert.NoError(t, parser.Parse(reflect.ValueOf(&target).Elem(), "nil"))
+	assert.Equal(t, status(0), target)
+
+	assert.Error(t, parser.Parse(reflect.ValueOf(&target).Elem(), "string"))
+	assert.Error(t, parser.Parse(reflect.ValueOf(&target).Elem(), "invalid"))
+}
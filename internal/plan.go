@@ -0,0 +1,808 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+// Package internal provides interfaces that are meant only for internal use.
+package internal
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Field contains all information which is needed in order to read a parameter into a struct field or
+// to write the field back as a parameter.
+type Field struct {
+	// Path is the name of the field combined with the names of all parent structs.
+	Path string
+	// Name is the Go name of the field combined with the Go names of all parent structs, separated by
+	// dots (e.g. "Period.From"). Names of embedded structs are omitted for promoted fields.
+	Name string
+	// Index is the sequence of field indexes which leads from the root struct to the field.
+	Index []int
+	// Type is the type of the field.
+	Type reflect.Type
+	// Tag is the struct tag of the field.
+	Tag reflect.StructTag
+	// Slice is true if the field (or the value type of a map) is a slice. Parser and Formatter then
+	// handle slice elements.
+	Slice bool
+	// Map is true if the field is a map. Parser and Formatter then handle map values (or their
+	// elements), KeyParser and KeyFormatter handle map keys.
+	Map bool
+	// Struct is true if the field is a struct (or a pointer to a struct) with fields of its own.
+	Struct bool
+	// Parser for the field value (or its elements), nil if the type is not supported.
+	Parser Parser
+	// Formatter for the field value (or its elements), nil if the type is not supported.
+	Formatter Formatter
+	// KeyParser for keys of map fields, nil if the key type is not supported.
+	KeyParser Parser
+	// KeyFormatter for keys of map fields, nil if the key type is not supported.
+	KeyFormatter Formatter
+	// Elem is the plan for slices of structs, nil for all other fields. Paths of the plan are relative
+	// to the slice elements.
+	Elem *Plan
+	// Default contains the values which are used if the parameter is missing, nil if there is no default.
+	Default []string
+	// Required is true if the parameter must not be missing.
+	Required bool
+	// Delimiter separates multiple elements of a slice within a single value, empty if each element
+	// is passed as a separate value.
+	Delimiter string
+	// Constraints are checked for each parsed value (or slice element) of the field.
+	Constraints []Constraint
+	// MaxItems is the maximum number of slice elements, zero if there is no limit.
+	MaxItems int
+	// Source is the name of the source of the field (see NamedSource), empty for parameters. The path
+	// of fields with a source is the name from the tag of the source, without parent structs.
+	Source string
+	// Flag is true for bool fields which are set to false if the parameter is missing (see
+	// Config.PresenceBools). Default values don't apply to flags, pointers (e.g. *bool) are no flags.
+	Flag bool
+	// File is true for fields which are assigned uploaded files (*multipart.FileHeader or a slice of it).
+	File bool
+	// MaxSize is the maximum size of each uploaded file in bytes, zero if there is no limit.
+	MaxSize int64
+	// Accept contains the accepted content types of uploaded files (see Accepts), nil if all types are
+	// accepted.
+	Accept []string
+	// Multiple is the policy for multiple values of single value fields (e.g. MultipleFirst), empty if
+	// multiple values are an error.
+	Multiple string
+	// JoinSeparator separates the joined values if the policy is MultipleJoin.
+	JoinSeparator string
+	// Aliases are further names of the field from the tag option "alias", in order of precedence. Like
+	// the path, aliases are combined with the names of all parent structs.
+	Aliases []string
+	// Deprecated contains the names of the field (the path and/or aliases) which are deprecated according
+	// to the tag option "deprecated".
+	Deprecated []string
+}
+
+// Policies for multiple values of single value fields.
+const (
+	MultipleError = "error"
+	MultipleFirst = "first"
+	MultipleLast  = "last"
+	MultipleJoin  = "join"
+)
+
+// Value returns the field of the provided root struct. Nil pointers to parent structs are replaced
+// by new values if alloc is true, otherwise the second returned value is false if such a nil pointer
+// was found.
+func (f *Field) Value(root reflect.Value, alloc bool) (reflect.Value, bool) {
+	value := root
+	for i, index := range f.Index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(index)
+	}
+	return value, true
+}
+
+// IsDeprecated returns true if the provided name of the field (the path or an alias) is deprecated.
+func (f *Field) IsDeprecated(name string) bool {
+	for _, deprecated := range f.Deprecated {
+		if deprecated == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan is the compiled list of all fields of a struct type and the fields of its child structs.
+// A plan is immutable and can therefore be shared between go routines.
+type Plan struct {
+	Fields []Field
+}
+
+// Config contains all settings which are needed in order to compile a plan.
+type Config struct {
+	// Tag is the struct tag which contains field names.
+	Tag string
+	// Mapper determines field names for fields without tag.
+	Mapper func(string) string
+	// Syntax defines how names of nested fields are combined.
+	Syntax Syntax
+	// DefaultTag is the struct tag which contains default values.
+	DefaultTag string
+	// Collection is the name of the collection format used for slice fields without collection
+	// format option (see Delimiters).
+	Collection string
+	// TypeParsers are custom parsers for specific types. They take precedence over all other parsers.
+	TypeParsers map[reflect.Type]Parser
+	// Parsers are further custom parsers, the first matching parser is used. They take precedence
+	// over the built-in parsers.
+	Parsers []CheckedParser
+	// Sources are further sources of values besides parameters, e.g. http headers.
+	Sources []NamedSource
+	// LayoutTag is the struct tag which contains the layout of time fields.
+	LayoutTag string
+	// TimeLayouts are the layouts which are tried in order to parse time fields without layout tag. If
+	// empty, such times are parsed using their UnmarshalText method.
+	TimeLayouts []string
+	// Location is used for times without time zone, nil for UTC.
+	Location *time.Location
+	// LenientBools enables the words yes/no, y/n and on/off for bool fields without custom parser.
+	LenientBools bool
+	// PresenceBools treats bool fields without custom parser as flags: empty values are parsed as true
+	// and missing parameters as false. Implies LenientBools.
+	PresenceBools bool
+	// Multiple is the policy for multiple values of single value fields without the tag "multiple",
+	// e.g. "first" or "join:,". Empty if multiple values are an error.
+	Multiple string
+}
+
+// NamedSource describes a source of values besides parameters. Fields are assigned to a source if their
+// tag for parameters is missing and the tag of the source is present.
+type NamedSource struct {
+	// Name identifies the source.
+	Name string
+	// Tag is the struct tag which contains the names of the values.
+	Tag string
+	// Normalize converts names from the tag into the form used by the source, nil if names are used
+	// as they are.
+	Normalize func(string) string
+}
+
+// FindParser finds a parser for the provided type. Custom parsers are preferred over the built-in ones.
+func (c *Config) FindParser(t reflect.Type) (Parser, bool) {
+	if parser, ok := c.TypeParsers[t]; ok {
+		return parser, true
+	}
+
+	for _, parser := range c.Parsers {
+		if parser.Check(t) {
+			return parser, true
+		}
+	}
+
+	parser, ok := FindParser(t)
+	if ok && t.Kind() == reflect.Bool && (c.LenientBools || c.PresenceBools) {
+		if _, text := parser.(textParser); !text {
+			parser = lenientBoolParser{presence: c.PresenceBools}
+		}
+	}
+	return parser, ok
+}
+
+// Compile creates a plan for the provided struct type. Field names are taken from the configured tag
+// or are determined by the mapper if the tag is missing. An error is returned if the struct tags of
+// the type contain invalid settings.
+func Compile(t reflect.Type, config *Config) (*Plan, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	c := compiler{config: config, visiting: map[reflect.Type]bool{}}
+	err := c.compile(t, "", "", nil)
+	if err == nil {
+		err = checkAliases(t, c.fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{Fields: c.fields}, nil
+}
+
+type compiler struct {
+	config   *Config
+	visiting map[reflect.Type]bool
+	fields   []Field
+}
+
+func (c *compiler) compile(t reflect.Type, prefix, parent string, index []int) error {
+	c.visiting[t] = true
+	defer delete(c.visiting, t)
+
+	for _, cand := range dominant(c.candidates(t, nil, 0, map[reflect.Type]bool{})) {
+		structField, options := cand.field, cand.options
+
+		field := Field{
+			Path:     c.config.Syntax.Join(prefix, cand.name),
+			Name:     structField.Name,
+			Index:    append(append(make([]int, 0, len(index)+len(cand.index)), index...), cand.index...),
+			Type:     structField.Type,
+			Tag:      structField.Tag,
+			Required: options.Has("required"),
+			Source:   cand.source,
+		}
+		if field.Source != "" {
+			field.Path = cand.name
+		}
+		if parent != "" {
+			field.Name = parent + "." + structField.Name
+		}
+
+		elem := c.elemType(&field)
+		err := c.values(&field, &cand, t, elem)
+		if err != nil {
+			return err
+		}
+
+		field.Constraints, err = compileConstraints(structField.Tag, field.Parser, elem)
+		if err == nil {
+			err = compileMaxItems(&field)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "invalid constraint for field %s of %s", structField.Name, t)
+		}
+
+		isStruct, err := c.nested(&field, &cand, t, elem)
+		if err != nil {
+			return err
+		}
+
+		err = c.aliases(&field, &cand, t, prefix, isStruct)
+		if err != nil {
+			return err
+		}
+
+		err = c.deprecated(&field, options, prefix, isStruct)
+		if err != nil {
+			return errors.Wrapf(err, "invalid deprecation for field %s of %s", structField.Name, t)
+		}
+
+		err = c.defaults(&field, &cand, t, elem)
+		if err != nil {
+			return err
+		}
+
+		if field.Required && (field.Struct || field.Map || field.Default != nil) {
+			return errors.Errorf("field %s of %s can't be required: struct fields, maps and fields with "+
+				"default values are not supported", structField.Name, t)
+		}
+
+		c.fields = append(c.fields, field)
+		if field.Struct {
+			err = c.compile(elem, field.Path, field.Name, field.Index)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// candidate is a struct field which is visible at the level of a struct, either declared by the struct
+// itself or promoted from an embedded struct.
+type candidate struct {
+	field   reflect.StructField
+	source  string
+	name    string
+	options tagOptions
+	tagged  bool
+	index   []int
+	depth   int
+}
+
+// key identifies the parameter (or the value of another source) which is read into the field
+func (cand *candidate) key() string {
+	if cand.source == "" {
+		return cand.name
+	}
+	return cand.source + ":" + cand.name
+}
+
+// candidates lists the exported fields of a struct type. Like encoding/json the fields of embedded
+// structs (and pointers to structs) are promoted to the level of the struct, unless the tag of the
+// embedded field contains a name.
+func (c *compiler) candidates(t reflect.Type, index []int, depth int, embedding map[reflect.Type]bool) []candidate {
+	embedding[t] = true
+	defer delete(embedding, t)
+
+	var cands []candidate
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+
+		// skip if tag is "-"
+		source, name, options := c.tag(structField)
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+
+		if structField.Anonymous {
+			elem := structField.Type
+			if elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+
+			if source == "" && name == "" && elem.Kind() == reflect.Struct && !c.handled(elem) {
+				// fields of unexported embedded structs can only be set if the struct is not a pointer
+				exported := structField.PkgPath == "" || structField.Type.Kind() != reflect.Ptr
+				if exported && !embedding[elem] {
+					cands = append(cands, c.candidates(elem, fieldIndex, depth+1, embedding)...)
+				}
+				continue
+			}
+		}
+
+		// skip if not exported
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		cand := candidate{field: structField, source: source, name: name, options: options, tagged: name != "",
+			index: fieldIndex, depth: depth}
+		if name == "" {
+			cand.name = c.config.Mapper(structField.Name)
+		}
+		cands = append(cands, cand)
+	}
+
+	return cands
+}
+
+// tag returns the source, the name and the options of a struct field. The tag for parameters takes
+// precedence over the tags of other sources. Names of other sources are normalized.
+func (c *compiler) tag(structField reflect.StructField) (string, string, tagOptions) {
+	if tag, ok := structField.Tag.Lookup(c.config.Tag); ok {
+		name, options := parseTag(tag)
+		return "", name, options
+	}
+
+	for _, source := range c.config.Sources {
+		if tag, ok := structField.Tag.Lookup(source.Tag); ok {
+			name, options := parseTag(tag)
+			if name == "" {
+				name = structField.Name
+			}
+			if source.Normalize != nil && name != "-" {
+				name = source.Normalize(name)
+			}
+			return source.Name, name, options
+		}
+	}
+
+	return "", "", nil
+}
+
+// handled returns true if values of the type are read and written as a whole.
+func (c *compiler) handled(t reflect.Type) bool {
+	_, parser := c.config.FindParser(t)
+	_, formatter := FindFormatter(t)
+	return parser || formatter || t == fileHeaderType
+}
+
+// dominant resolves conflicts between candidates with the same name using the rules of encoding/json:
+// the candidate with the lowest depth wins, between candidates with the same depth a single tagged
+// candidate wins. Otherwise all candidates with this name are dropped.
+func dominant(cands []candidate) []candidate {
+	byKey := make(map[string][]int, len(cands))
+	for i := range cands {
+		key := cands[i].key()
+		byKey[key] = append(byKey[key], i)
+	}
+
+	result := make([]candidate, 0, len(cands))
+	for i, cand := range cands {
+		if winner(cands, byKey[cand.key()]) == i {
+			result = append(result, cand)
+		}
+	}
+
+	return result
+}
+
+// winner returns the index of the dominant candidate or -1 if there is none
+func winner(cands []candidate, indexes []int) int {
+	if len(indexes) == 1 {
+		return indexes[0]
+	}
+
+	depth := -1
+	var shallowest []int
+	for _, i := range indexes {
+		if depth < 0 || cands[i].depth < depth {
+			depth = cands[i].depth
+			shallowest = shallowest[:0]
+		}
+		if cands[i].depth == depth {
+			shallowest = append(shallowest, i)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0]
+	}
+
+	win := -1
+	for _, i := range shallowest {
+		if cands[i].tagged {
+			if win >= 0 {
+				return -1
+			}
+			win = i
+		}
+	}
+	return win
+}
+
+// elemType marks maps and slices and returns the type of their elements (or the type of the field
+// otherwise) without pointer.
+func (c *compiler) elemType(field *Field) reflect.Type {
+	elem := field.Type
+	if elem.Kind() == reflect.Map {
+		field.Map = true
+		field.KeyParser, _ = c.config.FindParser(elem.Key())
+		field.KeyFormatter, _ = FindFormatter(elem.Key())
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Slice {
+		field.Slice = true
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem
+}
+
+// values configures how the values of the field are parsed and formatted: the collection format, parser,
+// formatter, time layouts, flags, uploaded files and the multiple value policy.
+func (c *compiler) values(field *Field, cand *candidate, t, elem reflect.Type) error {
+	structField := cand.field
+	field.Parser, _ = c.config.FindParser(elem)
+	field.Formatter, _ = FindFormatter(elem)
+
+	err := c.collection(field, cand.options)
+	if err != nil {
+		return errors.Wrapf(err, "invalid collection format for field %s of %s", structField.Name, t)
+	}
+
+	if elem == timeType {
+		c.timeLayouts(field, structField.Tag)
+	} else if _, ok := structField.Tag.Lookup(c.config.LayoutTag); ok && c.config.LayoutTag != "" {
+		return errors.Errorf("field %s of %s can't have a layout: only supported for time.Time", structField.Name, t)
+	}
+
+	if p, ok := field.Parser.(lenientBoolParser); ok && p.presence && field.Type.Kind() == reflect.Bool {
+		field.Flag = true
+	}
+
+	if elem == fileHeaderType {
+		err = compileFile(field)
+		if err != nil {
+			return errors.Wrapf(err, "invalid file field %s of %s", structField.Name, t)
+		}
+	} else {
+		for _, name := range fileTags {
+			if _, ok := structField.Tag.Lookup(name); ok {
+				return errors.Errorf("field %s of %s can't have the tag %s: only supported for files",
+					structField.Name, t, name)
+			}
+		}
+	}
+
+	err = c.multiple(field, structField.Tag, elem)
+	if err != nil {
+		return errors.Wrapf(err, "invalid multiple value policy for field %s of %s", structField.Name, t)
+	}
+
+	if field.Source != "" && field.Map {
+		return errors.Errorf("field %s of %s can't be read from %s: maps are not supported",
+			structField.Name, t, field.Source)
+	}
+	return nil
+}
+
+// compileMaxItems reads the maximum number of slice elements from the tag "maxitems".
+func compileMaxItems(field *Field) error {
+	limit, ok := field.Tag.Lookup("maxitems")
+	if !ok {
+		return nil
+	}
+
+	var err error
+	field.MaxItems, err = strconv.Atoi(limit)
+	if err != nil || field.MaxItems <= 0 || !field.Slice {
+		return errors.New("maxitems must be a positive integer and is only supported for slices")
+	}
+	return nil
+}
+
+// nested compiles the plan for the elements of slices of structs, which are read using indexes (e.g.
+// "items.0.name"), and marks struct fields whose fields are read individually. The returned value is
+// true if the field is a struct which can't be handled as a whole, also if it is a recursive type which
+// is not followed.
+func (c *compiler) nested(field *Field, cand *candidate, t, elem reflect.Type) (bool, error) {
+	composite := !field.Map && !field.File && field.Source == "" && elem.Kind() == reflect.Struct &&
+		field.Parser == nil && field.Formatter == nil
+
+	if composite && field.Slice {
+		if field.Delimiter != "" {
+			return false, errors.Errorf("field %s of %s can't have a collection format: slices of structs are "+
+				"not supported", cand.field.Name, t)
+		}
+
+		if !c.visiting[elem] {
+			sub := compiler{config: c.config, visiting: c.visiting}
+			err := sub.compile(elem, "", "", nil)
+			if err == nil {
+				err = checkAliases(elem, sub.fields)
+			}
+			if err != nil {
+				return false, err
+			}
+			field.Elem = &Plan{Fields: sub.fields}
+		}
+	}
+
+	isStruct := composite && !field.Slice
+	field.Struct = isStruct && !c.visiting[elem]
+	return isStruct, nil
+}
+
+// aliases reads the alternative names of the field from the tag option "alias", e.g. "alias=limit|size".
+func (c *compiler) aliases(field *Field, cand *candidate, t reflect.Type, prefix string, isStruct bool) error {
+	aliases, ok := cand.options.Value("alias")
+	if !ok {
+		return nil
+	}
+
+	if field.Source != "" || isStruct {
+		return errors.Errorf("field %s of %s can't have aliases: only supported for parameters which "+
+			"are no structs", cand.field.Name, t)
+	}
+	for _, alias := range strings.Split(aliases, "|") {
+		if alias == "" || alias == cand.name {
+			return errors.Errorf("field %s of %s has an invalid alias %q", cand.field.Name, t, alias)
+		}
+		field.Aliases = append(field.Aliases, c.config.Syntax.Join(prefix, alias))
+	}
+	return nil
+}
+
+// checkAliases ensures that each alias is used by a single field only and doesn't conflict with the name
+// of another field, since parameters with such a name would be read into several fields.
+func checkAliases(t reflect.Type, fields []Field) error {
+	owners := make(map[string]string, len(fields))
+	for i := range fields {
+		if fields[i].Source == "" && !fields[i].Struct {
+			owners[fields[i].Path] = fields[i].Name
+		}
+	}
+
+	for i := range fields {
+		for _, alias := range fields[i].Aliases {
+			if owner, ok := owners[alias]; ok {
+				return errors.Errorf("alias %q of field %s of %s conflicts with field %s", alias, fields[i].Name,
+					t, owner)
+			}
+			owners[alias] = fields[i].Name
+		}
+	}
+	return nil
+}
+
+// defaults reads the default value of the field from the configured default value tag. Default values
+// of slices without collection format are separated by commas.
+func (c *compiler) defaults(field *Field, cand *candidate, t, elem reflect.Type) error {
+	def, ok := field.Tag.Lookup(c.config.DefaultTag)
+	if !ok || c.config.DefaultTag == "" {
+		return nil
+	}
+
+	if field.Map || field.File {
+		return errors.Errorf("field %s of %s can't have a default value: maps and files are not supported",
+			cand.field.Name, t)
+	}
+
+	field.Default = []string{def}
+	if field.Slice && field.Delimiter == "" {
+		field.Default = strings.Split(def, ",")
+	}
+
+	err := checkDefault(field, elem)
+	if err != nil {
+		return errors.Wrapf(err, "invalid default value for field %s of %s", cand.field.Name, t)
+	}
+	return nil
+}
+
+// timeLayouts replaces the parser and formatter of time fields if a layout is declared by the layout tag
+// or by the configuration. Custom parsers take precedence over layouts from the configuration.
+func (c *compiler) timeLayouts(field *Field, tag reflect.StructTag) {
+	layouts := c.config.TimeLayouts
+	if layout, ok := tag.Lookup(c.config.LayoutTag); ok && c.config.LayoutTag != "" {
+		layouts = []string{layout}
+	} else if _, ok := field.Parser.(textParser); !ok || len(layouts) == 0 {
+		return
+	}
+
+	field.Parser = TimeParser{Layouts: layouts, Location: c.config.Location}
+	field.Formatter = TimeFormatter{Layout: layouts[0], Location: c.config.Location}
+}
+
+// deprecated determines the deprecated names of a field from the tag options: the option "deprecated"
+// deprecates all names of the field, "deprecated=a|b" only the listed names, which must be the name or
+// aliases of the field.
+func (c *compiler) deprecated(field *Field, options tagOptions, prefix string, isStruct bool) error {
+	listed, ok := options.Value("deprecated")
+	if !ok && !options.Has("deprecated") {
+		return nil
+	}
+	if field.Source != "" || isStruct {
+		return errors.New("only supported for parameters which are no structs")
+	}
+
+	names := append([]string{field.Path}, field.Aliases...)
+	if !ok {
+		field.Deprecated = names
+		return nil
+	}
+
+	for _, name := range strings.Split(listed, "|") {
+		path := c.config.Syntax.Join(prefix, name)
+		found := false
+		for _, n := range names {
+			found = found || n == path
+		}
+		if !found {
+			return errors.Errorf("%q is neither the name nor an alias of the field", name)
+		}
+		field.Deprecated = append(field.Deprecated, path)
+	}
+	return nil
+}
+
+// multiple determines the policy for multiple values of single value fields from the tag "multiple" or the
+// configuration. The policy join is only supported for strings, the configuration doesn't apply to
+// other types in this case.
+func (c *compiler) multiple(field *Field, tag reflect.StructTag, elem reflect.Type) error {
+	policy, tagged := tag.Lookup("multiple")
+	if !tagged {
+		policy = c.config.Multiple
+	}
+	if policy == "" {
+		return nil
+	}
+
+	name, sep, hasSep := strings.Cut(policy, ":")
+	switch {
+	case name == MultipleJoin && hasSep:
+		if field.File || elem.Kind() != reflect.String {
+			if tagged {
+				return errors.New("join is only supported for strings")
+			}
+			return nil
+		}
+		field.JoinSeparator = sep
+	case !hasSep && (name == MultipleError || name == MultipleFirst || name == MultipleLast):
+	default:
+		return errors.Errorf("unknown policy %q", policy)
+	}
+
+	if tagged && field.Slice {
+		return errors.New("only supported for single values")
+	}
+	if !field.Slice {
+		field.Multiple = name
+	}
+	return nil
+}
+
+// collection determines the delimiter of slice elements from the tag options or the configuration. The
+// configured format only applies to slices of values which can be parsed or formatted, e.g. not to slices
+// of structs or uploaded files.
+func (c *compiler) collection(field *Field, options tagOptions) error {
+	format := ""
+	for _, opt := range options {
+		if _, ok := Delimiters[opt]; ok {
+			if format != "" {
+				return errors.New("more than one collection format")
+			}
+			format = opt
+		}
+	}
+
+	if format != "" && !field.Slice {
+		return errors.New("collection formats are only supported for slices")
+	}
+
+	if format == "" {
+		if !field.Slice || c.config.Collection == "" || (field.Parser == nil && field.Formatter == nil) {
+			return nil
+		}
+		format = c.config.Collection
+	}
+
+	delim, ok := Delimiters[format]
+	if !ok {
+		return errors.Errorf("unknown collection format %q", format)
+	}
+
+	field.Delimiter = delim
+	return nil
+}
+
+// tagOptions are the comma separated options which follow the name in a struct tag
+type tagOptions []string
+
+// Has returns true if the tag options contain the provided option.
+func (opts tagOptions) Has(option string) bool {
+	for _, opt := range opts {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the value of an option in the form "key=value".
+func (opts tagOptions) Value(key string) (string, bool) {
+	for _, opt := range opts {
+		if k, value, ok := strings.Cut(opt, "="); ok && k == key {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// parseTag splits a struct tag into the name and its options
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], tagOptions(parts[1:])
+}
+
+// checkDefault parses the default values of a field in order to detect invalid values early
+func checkDefault(field *Field, elem reflect.Type) error {
+	if field.Parser == nil {
+		return errors.New("field type is not supported")
+	}
+
+	defaults, err := Split(field.Default, field.Delimiter)
+	if err != nil {
+		return err
+	}
+
+	for _, def := range defaults {
+		value := reflect.New(elem).Elem()
+		err := field.Parser.Parse(value, def)
+		if err != nil {
+			return err
+		}
+
+		for i := range field.Constraints {
+			if !field.Constraints[i].Check(value, def) {
+				return errors.Errorf("value %q violates constraint %s", def, field.Constraints[i].Name)
+			}
+		}
+	}
+
+	return nil
+}
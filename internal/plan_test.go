@@ -0,0 +1,541 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stoewer/go-qparam/internal"
+	"github.com/stoewer/go-strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+)
+
+var dot = internal.Syntax{Separator: "."}
+
+type outer struct {
+	FieldA    string
+	FiledB    int
+	C         bool    `param:"field_c"`
+	Skip      int     `param:"-"`
+	One       *innerA `param:"struct_one"`
+	StructTwo innerB
+	private   int
+}
+
+type innerA struct {
+	FieldD *int
+	FieldE *bool
+}
+
+type innerB struct {
+	FieldF      float64
+	StructThree innerC
+	FieldH      *float64
+	Time        time.Time
+}
+
+type innerC struct {
+	FieldG *string
+	FieldI []int
+}
+
+type recursive struct {
+	Name  string
+	Child *recursive
+}
+
+func TestCompile(t *testing.T) {
+	expected := []string{
+		"field_a",
+		"filed_b",
+		"field_c",
+		"struct_one",
+		"struct_one.field_d",
+		"struct_one.field_e",
+		"struct_two",
+		"struct_two.field_f",
+		"struct_two.struct_three",
+		"struct_two.struct_three.field_g",
+		"struct_two.struct_three.field_i",
+		"struct_two.field_h",
+		"struct_two.time",
+	}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+	plan, err := internal.Compile(reflect.TypeOf(&outer{}), config)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(plan.Fields))
+	for _, field := range plan.Fields {
+		paths = append(paths, field.Path)
+	}
+	assert.Equal(t, expected, paths)
+
+	byPath := make(map[string]internal.Field)
+	for _, field := range plan.Fields {
+		byPath[field.Path] = field
+	}
+
+	assert.True(t, byPath["struct_one"].Struct)
+	assert.Nil(t, byPath["struct_one"].Parser)
+	assert.False(t, byPath["struct_two.time"].Struct)
+	assert.NotNil(t, byPath["struct_two.time"].Parser)
+	assert.True(t, byPath["struct_two.struct_three.field_i"].Slice)
+	assert.NotNil(t, byPath["struct_two.struct_three.field_i"].Parser)
+	assert.Equal(t, []int{5, 1, 0}, byPath["struct_two.struct_three.field_g"].Index)
+	assert.Equal(t, "StructTwo.StructThree.FieldG", byPath["struct_two.struct_three.field_g"].Name)
+}
+
+func TestCompile_Recursive(t *testing.T) {
+	expected := []string{"name", "child"}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+	plan, err := internal.Compile(reflect.TypeOf(recursive{}), config)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(plan.Fields))
+	for _, field := range plan.Fields {
+		paths = append(paths, field.Path)
+	}
+	assert.Equal(t, expected, paths)
+	assert.False(t, plan.Fields[1].Struct)
+}
+
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+type sorting struct {
+	Sort  string
+	Order string
+}
+
+type Filter struct {
+	Name string
+	Sort string `param:"sort"`
+}
+
+type embedding struct {
+	*Pagination
+	sorting
+	Filter
+	Nested Pagination
+	Named  Pagination `param:"page"`
+	Offset uint
+	Time   time.Time `param:",required"`
+	time.Duration
+}
+
+func TestCompile_Embedded(t *testing.T) {
+	expected := []string{
+		"limit",
+		"order",
+		"name",
+		"sort",
+		"nested",
+		"nested.limit",
+		"nested.offset",
+		"page",
+		"page.limit",
+		"page.offset",
+		"offset",
+		"time",
+		"duration",
+	}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+	plan, err := internal.Compile(reflect.TypeOf(embedding{}), config)
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(plan.Fields))
+	byPath := make(map[string]internal.Field)
+	for _, field := range plan.Fields {
+		paths = append(paths, field.Path)
+		byPath[field.Path] = field
+	}
+	assert.Equal(t, expected, paths)
+	assert.Equal(t, []int{0, 0}, byPath["limit"].Index)
+	assert.Equal(t, []int{2, 1}, byPath["sort"].Index, "tagged field must win")
+	assert.Equal(t, []int{5}, byPath["offset"].Index, "shallow field must win")
+
+	t.Run("conflict", func(t *testing.T) {
+		type conflict struct {
+			Pagination
+			Other Pagination `param:"-"`
+			More  struct{ Limit int }
+		}
+
+		type ambiguous struct {
+			Pagination
+			conflict
+		}
+
+		config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+		plan, err := internal.Compile(reflect.TypeOf(ambiguous{}), config)
+		require.NoError(t, err)
+
+		paths := make([]string, 0, len(plan.Fields))
+		for _, field := range plan.Fields {
+			paths = append(paths, field.Path)
+		}
+		assert.Equal(t, []string{"limit", "offset", "more", "more.limit"}, paths)
+	})
+
+	t.Run("value", func(t *testing.T) {
+		limit, order := byPath["limit"], byPath["order"]
+		data := embedding{}
+		value, ok := limit.Value(reflect.ValueOf(&data).Elem(), true)
+		require.True(t, ok)
+		require.NotNil(t, data.Pagination)
+
+		value.SetInt(10)
+		assert.Equal(t, 10, data.Limit)
+
+		value, ok = order.Value(reflect.ValueOf(&data).Elem(), true)
+		require.True(t, ok)
+		value.SetString("desc")
+		assert.Equal(t, "desc", data.Order)
+	})
+}
+
+func TestCompile_Sources(t *testing.T) {
+	config := &internal.Config{
+		Tag:    "param",
+		Mapper: strcase.SnakeCase,
+		Syntax: dot,
+		Sources: []internal.NamedSource{
+			{Name: "header", Tag: "header", Normalize: strings.ToUpper},
+			{Name: "cookie", Tag: "cookie"},
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		type nested struct {
+			Token string `cookie:"token"`
+		}
+
+		type sources struct {
+			Limit   int    `param:"limit" header:"ignored"`
+			Limit2  int    `header:"limit"`
+			Session string `cookie:"Session"`
+			Nested  nested
+		}
+
+		plan, err := internal.Compile(reflect.TypeOf(sources{}), config)
+		require.NoError(t, err)
+
+		keys := make([]string, 0, len(plan.Fields))
+		for _, field := range plan.Fields {
+			keys = append(keys, field.Source+":"+field.Path)
+		}
+		assert.Equal(t, []string{":limit", "header:LIMIT", "cookie:Session", ":nested", "cookie:token"}, keys)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		type sources struct {
+			Labels map[string]string `header:"labels"`
+		}
+
+		_, err := internal.Compile(reflect.TypeOf(sources{}), config)
+		assert.Error(t, err)
+	})
+}
+
+func TestField_Value(t *testing.T) {
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+	plan, err := internal.Compile(reflect.TypeOf(outer{}), config)
+	require.NoError(t, err)
+
+	var fieldD *internal.Field
+	for i := range plan.Fields {
+		if plan.Fields[i].Path == "struct_one.field_d" {
+			fieldD = &plan.Fields[i]
+		}
+	}
+	require.NotNil(t, fieldD)
+
+	data := outer{}
+	_, ok := fieldD.Value(reflect.ValueOf(&data).Elem(), false)
+	assert.False(t, ok)
+	assert.Nil(t, data.One)
+
+	value, ok := fieldD.Value(reflect.ValueOf(&data).Elem(), true)
+	assert.True(t, ok)
+	require.NotNil(t, data.One)
+
+	value.Set(reflect.ValueOf(new(int)))
+	assert.NotNil(t, data.One.FieldD)
+}
+
+func TestCompile_Default(t *testing.T) {
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot, DefaultTag: "default"}
+
+	t.Run("valid", func(t *testing.T) {
+		type defaults struct {
+			Single  int        `default:"25"`
+			Slice   []int      `default:"1,2,3"`
+			Pointer *time.Time `default:"2017-06-01T12:00:00Z"`
+			None    string
+		}
+
+		plan, err := internal.Compile(reflect.TypeOf(defaults{}), config)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"25"}, plan.Fields[0].Default)
+		assert.Equal(t, []string{"1", "2", "3"}, plan.Fields[1].Default)
+		assert.Equal(t, []string{"2017-06-01T12:00:00Z"}, plan.Fields[2].Default)
+		assert.Nil(t, plan.Fields[3].Default)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		type defaults struct {
+			Slice []int `default:"1,two"`
+		}
+
+		_, err := internal.Compile(reflect.TypeOf(defaults{}), config)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		type defaults struct {
+			Map map[string]string `default:"foo"`
+		}
+
+		_, err := internal.Compile(reflect.TypeOf(defaults{}), config)
+		assert.Error(t, err)
+	})
+}
+
+func TestCompile_Required(t *testing.T) {
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+
+	type required struct {
+		Limit  int `param:"limit,required"`
+		Offset int `param:",required"`
+		Sort   string
+	}
+
+	plan, err := internal.Compile(reflect.TypeOf(required{}), config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "limit", plan.Fields[0].Path)
+	assert.True(t, plan.Fields[0].Required)
+	assert.Equal(t, "offset", plan.Fields[1].Path)
+	assert.True(t, plan.Fields[1].Required)
+	assert.False(t, plan.Fields[2].Required)
+
+	type invalid struct {
+		Struct innerA `param:",required"`
+	}
+
+	_, err = internal.Compile(reflect.TypeOf(invalid{}), config)
+	assert.Error(t, err)
+}
+
+func TestCompile_Flag(t *testing.T) {
+	type flags struct {
+		Verbose bool
+		Debug   *bool
+		Levels  []bool
+		Toggles map[string]bool
+		Null    null.Bool
+	}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot, PresenceBools: true}
+	plan, err := internal.Compile(reflect.TypeOf(flags{}), config)
+	require.NoError(t, err)
+
+	assert.True(t, plan.Fields[0].Flag)
+	assert.False(t, plan.Fields[1].Flag)
+	assert.False(t, plan.Fields[2].Flag)
+	assert.False(t, plan.Fields[3].Flag)
+	assert.False(t, plan.Fields[4].Flag)
+
+	config = &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot, LenientBools: true}
+	plan, err = internal.Compile(reflect.TypeOf(flags{}), config)
+	require.NoError(t, err)
+
+	assert.False(t, plan.Fields[0].Flag)
+}
+
+func TestCompile_Multiple(t *testing.T) {
+	type policies struct {
+		Sort   string `multiple:"last"`
+		Tags   string `multiple:"join:,"`
+		Limit  int
+		Name   string
+		Levels []string
+	}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot, Multiple: "join: "}
+	plan, err := internal.Compile(reflect.TypeOf(policies{}), config)
+	require.NoError(t, err)
+
+	assert.Equal(t, internal.MultipleLast, plan.Fields[0].Multiple)
+	assert.Equal(t, internal.MultipleJoin, plan.Fields[1].Multiple)
+	assert.Equal(t, ",", plan.Fields[1].JoinSeparator)
+	assert.Equal(t, "", plan.Fields[2].Multiple, "join must not apply to ints")
+	assert.Equal(t, internal.MultipleJoin, plan.Fields[3].Multiple)
+	assert.Equal(t, " ", plan.Fields[3].JoinSeparator)
+	assert.Equal(t, "", plan.Fields[4].Multiple, "policies must not apply to slices")
+
+	invalid := []interface{}{
+		struct {
+			Limit int `multiple:"join:,"`
+		}{},
+		struct {
+			Tags []string `multiple:"first"`
+		}{},
+		struct {
+			Sort string `multiple:"any"`
+		}{},
+		struct {
+			Sort string `multiple:"first:"`
+		}{},
+	}
+
+	for _, target := range invalid {
+		_, err = internal.Compile(reflect.TypeOf(target), config)
+		assert.Error(t, err, "%T", target)
+	}
+
+	config = &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot, Multiple: "middle"}
+	_, err = internal.Compile(reflect.TypeOf(policies{}), config)
+	assert.Error(t, err)
+}
+
+func TestCompile_Aliases(t *testing.T) {
+	type inner struct {
+		PageSize int `param:"page_size,alias=per_page|limit"`
+	}
+
+	type aliases struct {
+		Inner inner
+		Tags  []string `param:",csv,alias=tag"`
+	}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+	plan, err := internal.Compile(reflect.TypeOf(aliases{}), config)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(plan.Fields))
+
+	assert.Equal(t, "inner.page_size", plan.Fields[1].Path)
+	assert.Equal(t, []string{"inner.per_page", "inner.limit"}, plan.Fields[1].Aliases)
+	assert.Equal(t, "tags", plan.Fields[2].Path)
+	assert.Equal(t, []string{"tag"}, plan.Fields[2].Aliases)
+	assert.Equal(t, ",", plan.Fields[2].Delimiter)
+
+	invalid := []interface{}{
+		struct {
+			Inner inner `param:",alias=nested"`
+		}{},
+		struct {
+			Limit int `param:"limit,alias="`
+		}{},
+		struct {
+			Limit int `param:"limit,alias=limit"`
+		}{},
+		struct {
+			Limit int `param:"limit,alias=a||b"`
+		}{},
+		struct {
+			Limit int `param:"limit"`
+			Size  int `param:"size,alias=limit"`
+		}{},
+		struct {
+			Size  int `param:"size,alias=limit"`
+			Limit int `param:"limit"`
+		}{},
+		struct {
+			Size  int `param:"size,alias=count"`
+			Limit int `param:"limit,alias=count"`
+		}{},
+		struct {
+			Items []struct {
+				Limit int `param:"limit"`
+				Size  int `param:"size,alias=limit"`
+			}
+		}{},
+	}
+
+	for _, target := range invalid {
+		_, err = internal.Compile(reflect.TypeOf(target), config)
+		assert.Error(t, err, "%T", target)
+	}
+}
+
+func TestCompile_Deprecated(t *testing.T) {
+	type inner struct {
+		Limit int `param:"limit,alias=per_page|pageSize,deprecated=per_page"`
+	}
+
+	type deprecated struct {
+		Inner inner
+		Sort  string `param:"sort,alias=order,deprecated"`
+		Page  int
+	}
+
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+	plan, err := internal.Compile(reflect.TypeOf(deprecated{}), config)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(plan.Fields))
+
+	limit := plan.Fields[1]
+	assert.Equal(t, []string{"inner.per_page"}, limit.Deprecated)
+	assert.True(t, limit.IsDeprecated("inner.per_page"))
+	assert.False(t, limit.IsDeprecated("inner.limit"))
+	assert.False(t, limit.IsDeprecated("inner.pageSize"))
+
+	sort := plan.Fields[2]
+	assert.Equal(t, []string{"sort", "order"}, sort.Deprecated)
+	assert.Nil(t, plan.Fields[3].Deprecated)
+
+	invalid := []interface{}{
+		struct {
+			Limit int `param:"limit,alias=per_page,deprecated=page_size"`
+		}{},
+		struct {
+			Inner inner `param:",deprecated"`
+		}{},
+	}
+
+	for _, target := range invalid {
+		_, err = internal.Compile(reflect.TypeOf(target), config)
+		assert.Error(t, err, "%T", target)
+	}
+}
+
+func TestCompile_Map(t *testing.T) {
+	config := &internal.Config{Tag: "param", Mapper: strcase.SnakeCase, Syntax: dot}
+
+	type maps struct {
+		Labels  map[string]string
+		Values  map[int][]*int
+		Structs map[string]innerA
+	}
+
+	plan, err := internal.Compile(reflect.TypeOf(maps{}), config)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(plan.Fields))
+
+	assert.True(t, plan.Fields[0].Map)
+	assert.False(t, plan.Fields[0].Slice)
+	assert.NotNil(t, plan.Fields[0].KeyParser)
+	assert.NotNil(t, plan.Fields[0].Parser)
+
+	assert.True(t, plan.Fields[1].Map)
+	assert.True(t, plan.Fields[1].Slice)
+	assert.NotNil(t, plan.Fields[1].KeyParser)
+	assert.NotNil(t, plan.Fields[1].Parser)
+
+	assert.True(t, plan.Fields[2].Map)
+	assert.False(t, plan.Fields[2].Struct)
+	assert.Nil(t, plan.Fields[2].Parser)
+}
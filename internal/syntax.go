@@ -0,0 +1,142 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Syntax defines how the names of nested fields, slice elements and map entries are combined
+// into parameter names.
+type Syntax struct {
+	// Separator is placed between the names of nested fields, e.g. "contact.phone.label".
+	Separator string
+	// Brackets enables the bracket notation, e.g. "contact[phone][label]". The separator is
+	// ignored if brackets are enabled.
+	Brackets bool
+}
+
+// Join appends the relative path of a nested field (or the index of a slice element)
+// to the provided prefix.
+func (s Syntax) Join(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if !s.Brackets {
+		return prefix + s.Separator + path
+	}
+
+	i := strings.IndexByte(path, '[')
+	if i < 0 {
+		return prefix + "[" + path + "]"
+	}
+	return prefix + "[" + path[:i] + "]" + path[i:]
+}
+
+// Key returns the map key if the provided name refers to an entry of the map with the
+// provided path. Besides the configured syntax the bracket notation is always accepted
+// for map keys.
+func (s Syntax) Key(name, path string) (string, bool) {
+	if len(name) <= len(path)+1 || !strings.HasPrefix(name, path) {
+		return "", false
+	}
+
+	rest := name[len(path):]
+	if rest[0] == '[' && rest[len(rest)-1] == ']' {
+		return rest[1 : len(rest)-1], true
+	}
+	if !s.Brackets && strings.HasPrefix(rest, s.Separator) && len(rest) > len(s.Separator) {
+		return rest[len(s.Separator):], true
+	}
+
+	return "", false
+}
+
+// Index returns the index if the provided name refers to a slice element (or one of its
+// nested fields) of the slice with the provided path. The second value is true if the name
+// refers to the element itself and not to a nested field.
+func (s Syntax) Index(name, path string) (int, bool, bool) {
+	if !strings.HasPrefix(name, path) {
+		return 0, false, false
+	}
+
+	var digits, rest string
+	if s.Brackets {
+		rest = name[len(path):]
+		if !strings.HasPrefix(rest, "[") {
+			return 0, false, false
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return 0, false, false
+		}
+		digits, rest = rest[1:end], rest[end+1:]
+		if rest != "" && rest[0] != '[' {
+			return 0, false, false
+		}
+	} else {
+		rest = name[len(path):]
+		if !strings.HasPrefix(rest, s.Separator) {
+			return 0, false, false
+		}
+		rest = rest[len(s.Separator):]
+		digits = rest
+		if end := strings.Index(rest, s.Separator); end >= 0 {
+			digits, rest = rest[:end], rest[end:]
+		} else {
+			rest = ""
+		}
+	}
+
+	if digits == "" || digits[0] == '+' || digits[0] == '-' {
+		return 0, false, false
+	}
+	index, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, false, false
+	}
+
+	return index, rest == "", true
+}
+
+// Normalize converts parameter names in bracket notation into the canonical form which is used
+// for field paths: "contact[phone].label" becomes "contact[phone][label]" and empty brackets at
+// the end of a name (e.g. "ids[]") are removed. Names are not changed if brackets are disabled
+// or if the name is malformed.
+func (s Syntax) Normalize(name string) string {
+	if !s.Brackets || !strings.ContainsAny(name, "[.") {
+		return name
+	}
+
+	end := strings.IndexAny(name, "[.")
+	normalized, rest := name[:end], name[end:]
+	for rest != "" {
+		var segment string
+		if rest[0] == '[' {
+			end = strings.IndexByte(rest, ']')
+			if end < 0 {
+				return name
+			}
+			segment, rest = rest[1:end], rest[end+1:]
+			if segment == "" && rest == "" {
+				break
+			}
+		} else {
+			rest = rest[1:]
+			end = strings.IndexAny(rest, "[.")
+			if end < 0 {
+				end = len(rest)
+			}
+			segment, rest = rest[:end], rest[end:]
+		}
+
+		if segment == "" {
+			return name
+		}
+		normalized = normalized + "[" + segment + "]"
+	}
+
+	return normalized
+}
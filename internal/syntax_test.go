@@ -0,0 +1,119 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/stoewer/go-qparam/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	underscores = internal.Syntax{Separator: "__"}
+	brackets    = internal.Syntax{Separator: ".", Brackets: true}
+)
+
+func TestSyntax_Join(t *testing.T) {
+	data := []struct {
+		Syntax   internal.Syntax
+		Prefix   string
+		Path     string
+		Expected string
+	}{
+		{Syntax: dot, Prefix: "", Path: "name", Expected: "name"},
+		{Syntax: dot, Prefix: "contact", Path: "phone.label", Expected: "contact.phone.label"},
+		{Syntax: underscores, Prefix: "contact", Path: "phone", Expected: "contact__phone"},
+		{Syntax: brackets, Prefix: "", Path: "name", Expected: "name"},
+		{Syntax: brackets, Prefix: "contact", Path: "phone", Expected: "contact[phone]"},
+		{Syntax: brackets, Prefix: "items[0]", Path: "phone[label]", Expected: "items[0][phone][label]"},
+	}
+
+	for _, tt := range data {
+		assert.Equal(t, tt.Expected, tt.Syntax.Join(tt.Prefix, tt.Path))
+	}
+}
+
+func TestSyntax_Key(t *testing.T) {
+	data := []struct {
+		Syntax   internal.Syntax
+		Name     string
+		Path     string
+		Expected string
+		OK       bool
+	}{
+		{Syntax: dot, Name: "labels.env", Path: "labels", Expected: "env", OK: true},
+		{Syntax: dot, Name: "labels[env]", Path: "labels", Expected: "env", OK: true},
+		{Syntax: dot, Name: "labels.a.b", Path: "labels", Expected: "a.b", OK: true},
+		{Syntax: dot, Name: "labels", Path: "labels"},
+		{Syntax: dot, Name: "labels.", Path: "labels"},
+		{Syntax: dot, Name: "labelsenv", Path: "labels"},
+		{Syntax: underscores, Name: "labels__env", Path: "labels", Expected: "env", OK: true},
+		{Syntax: underscores, Name: "labels.env", Path: "labels"},
+		{Syntax: brackets, Name: "labels[env]", Path: "labels", Expected: "env", OK: true},
+		{Syntax: brackets, Name: "labels.env", Path: "labels"},
+	}
+
+	for _, tt := range data {
+		key, ok := tt.Syntax.Key(tt.Name, tt.Path)
+		assert.Equal(t, tt.OK, ok, tt.Name)
+		assert.Equal(t, tt.Expected, key, tt.Name)
+	}
+}
+
+func TestSyntax_Index(t *testing.T) {
+	data := []struct {
+		Syntax   internal.Syntax
+		Name     string
+		Path     string
+		Expected int
+		Exact    bool
+		OK       bool
+	}{
+		{Syntax: dot, Name: "ids.3", Path: "ids", Expected: 3, Exact: true, OK: true},
+		{Syntax: dot, Name: "items.12.name", Path: "items", Expected: 12, OK: true},
+		{Syntax: dot, Name: "items.x.name", Path: "items"},
+		{Syntax: dot, Name: "items.-1", Path: "items"},
+		{Syntax: dot, Name: "items.+1", Path: "items"},
+		{Syntax: dot, Name: "items", Path: "items"},
+		{Syntax: dot, Name: "items3", Path: "items"},
+		{Syntax: underscores, Name: "items__2__name", Path: "items", Expected: 2, OK: true},
+		{Syntax: brackets, Name: "ids[3]", Path: "ids", Expected: 3, Exact: true, OK: true},
+		{Syntax: brackets, Name: "items[12][name]", Path: "items", Expected: 12, OK: true},
+		{Syntax: brackets, Name: "items[12]name", Path: "items"},
+		{Syntax: brackets, Name: "items[]", Path: "items"},
+		{Syntax: brackets, Name: "items[3", Path: "items"},
+	}
+
+	for _, tt := range data {
+		index, exact, ok := tt.Syntax.Index(tt.Name, tt.Path)
+		assert.Equal(t, tt.OK, ok, tt.Name)
+		assert.Equal(t, tt.Exact, exact, tt.Name)
+		assert.Equal(t, tt.Expected, index, tt.Name)
+	}
+}
+
+func TestSyntax_Normalize(t *testing.T) {
+	data := []struct {
+		Syntax   internal.Syntax
+		Name     string
+		Expected string
+	}{
+		{Syntax: dot, Name: "contact[phone].label", Expected: "contact[phone].label"},
+		{Syntax: brackets, Name: "name", Expected: "name"},
+		{Syntax: brackets, Name: "contact[phone][label]", Expected: "contact[phone][label]"},
+		{Syntax: brackets, Name: "contact[phone].label", Expected: "contact[phone][label]"},
+		{Syntax: brackets, Name: "contact.phone.label", Expected: "contact[phone][label]"},
+		{Syntax: brackets, Name: "items[0].name", Expected: "items[0][name]"},
+		{Syntax: brackets, Name: "labels[a.b]", Expected: "labels[a.b]"},
+		{Syntax: brackets, Name: "ids[]", Expected: "ids"},
+		{Syntax: brackets, Name: "items[][name]", Expected: "items[][name]"},
+		{Syntax: brackets, Name: "contact[phone", Expected: "contact[phone"},
+		{Syntax: brackets, Name: "contact..phone", Expected: "contact..phone"},
+	}
+
+	for _, tt := range data {
+		assert.Equal(t, tt.Expected, tt.Syntax.Normalize(tt.Name), tt.Name)
+	}
+}
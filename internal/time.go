@@ -0,0 +1,130 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Layouts for times which are passed as number of seconds or milliseconds since January 1, 1970 UTC.
+const (
+	UnixLayout      = "unix"
+	UnixMilliLayout = "unixmilli"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// durationParser parses durations like "1h30m" using time.ParseDuration
+type durationParser struct{}
+
+func (durationParser) Check(t reflect.Type) bool {
+	return t == durationType
+}
+
+func (durationParser) Parse(value reflect.Value, s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	value.SetInt(int64(d))
+	return nil
+}
+
+// durationFormatter formats durations in the format used by durationParser
+type durationFormatter struct{}
+
+func (durationFormatter) Check(t reflect.Type) bool {
+	return t == durationType
+}
+
+func (durationFormatter) Format(value reflect.Value) (string, error) {
+	return time.Duration(value.Int()).String(), nil
+}
+
+// TimeParser parses times using a list of layouts which are tried in order. Besides the layouts
+// supported by time.Parse, the layouts UnixLayout and UnixMilliLayout are supported. Times without
+// time zone are parsed in the provided location (default: UTC).
+type TimeParser struct {
+	Layouts  []string
+	Location *time.Location
+}
+
+// Check returns true for time.Time.
+func (p TimeParser) Check(t reflect.Type) bool {
+	return t == timeType
+}
+
+// Parse parses the string using the first matching layout.
+func (p TimeParser) Parse(value reflect.Value, s string) error {
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var err error
+	for _, layout := range p.Layouts {
+		var t time.Time
+		t, err = parseTime(layout, s, loc)
+		if err == nil {
+			value.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+
+	if len(p.Layouts) > 1 {
+		return errors.Errorf("time %q doesn't match any of the layouts %q", s, p.Layouts)
+	}
+	return err
+}
+
+func parseTime(layout, s string, loc *time.Location) (time.Time, error) {
+	switch layout {
+	case UnixLayout, UnixMilliLayout:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if layout == UnixLayout {
+			return time.Unix(i, 0).In(loc), nil
+		}
+		return time.Unix(i/1000, i%1000*int64(time.Millisecond)).In(loc), nil
+	}
+	return time.ParseInLocation(layout, s, loc)
+}
+
+// TimeFormatter formats times using the provided layout, which can also be UnixLayout or UnixMilliLayout.
+// Times are converted into the provided location (default: UTC) before they are formatted.
+type TimeFormatter struct {
+	Layout   string
+	Location *time.Location
+}
+
+// Check returns true for time.Time.
+func (f TimeFormatter) Check(t reflect.Type) bool {
+	return t == timeType
+}
+
+// Format formats the time using the layout.
+func (f TimeFormatter) Format(value reflect.Value) (string, error) {
+	t := value.Interface().(time.Time)
+	switch f.Layout {
+	case UnixLayout:
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case UnixMilliLayout:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10), nil
+	}
+
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(f.Layout), nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stoewer/go-qparam/internal"
+	"github.com/stoewer/go-strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectParser_Duration(t *testing.T) {
+	var d time.Duration
+	parser, ok := internal.FindParser(reflect.TypeOf(d))
+	require.True(t, ok)
+
+	err := parser.Parse(reflect.ValueOf(&d).Elem(), "1h30m")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	err = parser.Parse(reflect.ValueOf(&d).Elem(), "5")
+	assert.Error(t, err)
+
+	formatter, ok := internal.FindFormatter(reflect.TypeOf(d))
+	require.True(t, ok)
+	s, err := formatter.Format(reflect.ValueOf(d))
+	assert.NoError(t, err)
+	assert.Equal(t, "1h30m0s", s)
+}
+
+func TestTimeParser_Parse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	data := []struct {
+		Name        string
+		Layouts     []string
+		Location    *time.Location
+		Value       string
+		Expected    time.Time
+		ExpectedErr bool
+	}{
+		{
+			Name:     "date",
+			Layouts:  []string{"2006-01-02"},
+			Value:    "2020-02-29",
+			Expected: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:     "date in location",
+			Layouts:  []string{"2006-01-02"},
+			Location: berlin,
+			Value:    "2020-02-29",
+			Expected: time.Date(2020, 2, 29, 0, 0, 0, 0, berlin),
+		},
+		{
+			Name:     "second layout",
+			Layouts:  []string{"2006-01-02", time.RFC3339},
+			Value:    "2020-02-29T10:00:00+01:00",
+			Expected: time.Date(2020, 2, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:     "unix",
+			Layouts:  []string{internal.UnixLayout},
+			Value:    "1582970400",
+			Expected: time.Date(2020, 2, 29, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:     "unix milli",
+			Layouts:  []string{internal.UnixMilliLayout},
+			Value:    "1582970400123",
+			Expected: time.Date(2020, 2, 29, 10, 0, 0, 123000000, time.UTC),
+		},
+		{
+			Name:        "invalid",
+			Layouts:     []string{"2006-01-02", internal.UnixLayout},
+			Value:       "yesterday",
+			ExpectedErr: true,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.Name, func(t *testing.T) {
+			var actual time.Time
+			parser := internal.TimeParser{Layouts: tt.Layouts, Location: tt.Location}
+
+			err := parser.Parse(reflect.ValueOf(&actual).Elem(), tt.Value)
+			if tt.ExpectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, tt.Expected.Equal(actual), "expected %s but was %s", tt.Expected, actual)
+				if tt.Location != nil {
+					assert.Equal(t, tt.Location, actual.Location())
+				}
+			}
+		})
+	}
+}
+
+func TestTimeFormatter_Format(t *testing.T) {
+	value := reflect.ValueOf(time.Date(2020, 2, 29, 23, 30, 0, 5000000, time.UTC))
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	data := []struct {
+		Layout   string
+		Location *time.Location
+		Expected string
+	}{
+		{Layout: "2006-01-02", Expected: "2020-02-29"},
+		{Layout: "2006-01-02", Location: berlin, Expected: "2020-03-01"},
+		{Layout: internal.UnixLayout, Expected: "1583019000"},
+		{Layout: internal.UnixMilliLayout, Expected: "1583019000005"},
+	}
+
+	for _, tt := range data {
+		formatter := internal.TimeFormatter{Layout: tt.Layout, Location: tt.Location}
+		actual, err := formatter.Format(value)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Expected, actual)
+	}
+}
+
+func TestCompile_Layout(t *testing.T) {
+	config := &internal.Config{Tag: "param", LayoutTag: "layout", Mapper: strcase.SnakeCase, Syntax: dot}
+
+	type times struct {
+		Default time.Time
+		Date    *time.Time  `layout:"2006-01-02"`
+		Dates   []time.Time `layout:"unix"`
+	}
+
+	plan, err := internal.Compile(reflect.TypeOf(times{}), config)
+	require.NoError(t, err)
+	assert.IsType(t, internal.TimeParser{}, plan.Fields[1].Parser)
+	assert.IsType(t, internal.TimeFormatter{}, plan.Fields[1].Formatter)
+	assert.IsType(t, internal.TimeParser{}, plan.Fields[2].Parser)
+	assert.NotEqual(t, internal.TimeParser{}, plan.Fields[0].Parser)
+
+	config.TimeLayouts = []string{time.RFC1123}
+	plan, err = internal.Compile(reflect.TypeOf(times{}), config)
+	require.NoError(t, err)
+	assert.Equal(t, internal.TimeParser{Layouts: []string{time.RFC1123}}, plan.Fields[0].Parser)
+	assert.Equal(t, internal.TimeParser{Layouts: []string{"2006-01-02"}}, plan.Fields[1].Parser)
+
+	_, err = internal.Compile(reflect.TypeOf(struct {
+		Name string `layout:"2006-01-02"`
+	}{}), config)
+	assert.Error(t, err)
+}
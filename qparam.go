@@ -7,18 +7,27 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net/http"
 	"net/url"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stoewer/go-qparam/internal"
 )
 
 var (
-	defaultTag    = "param"
-	defaultMapper = strings.ToLower
+	defaultTag       = "param"
+	defaultValueTag  = "default"
+	defaultMapper    = strings.ToLower
+	defaultSeparator = "."
+	defaultMaxIndex  = 1000
+	defaultLayoutTag = "layout"
 )
 
 // Option is a functional option which can be applied to a reader.
@@ -28,7 +37,7 @@ type Option func(*Reader)
 // the reader.
 func Mapper(mapper func(string) string) Option {
 	return func(r *Reader) {
-		r.mapper = mapper
+		r.config.Mapper = mapper
 	}
 }
 
@@ -36,7 +45,178 @@ func Mapper(mapper func(string) string) Option {
 // reader.
 func Tag(tag string) Option {
 	return func(r *Reader) {
-		r.tag = tag
+		r.config.Tag = tag
+	}
+}
+
+// Separator is a functional option which allows to specify the separator which is placed between the
+// names of nested fields, slice indexes or map keys (default: "."), e.g. "contact.phone.label" or
+// "items.0.name".
+func Separator(sep string) Option {
+	return func(r *Reader) {
+		r.config.Syntax.Separator = sep
+	}
+}
+
+// Brackets is a functional option which enables the bracket notation for nested fields, slice indexes
+// and map keys (default: false), as used by many web frameworks and libraries: "contact[phone][label]",
+// "items[0][name]" or "labels[env]". Names which mix both notations (e.g. "items[0].name") and names
+// of slices with empty brackets (e.g. "ids[]") are accepted as well. If brackets are enabled, the
+// separator is ignored.
+func Brackets(brackets bool) Option {
+	return func(r *Reader) {
+		r.config.Syntax.Brackets = brackets
+	}
+}
+
+// MaxIndex is a functional option which limits the indexes of slice elements (default: 1000), e.g. the
+// parameter "items.1001.name" is rejected by default. Parameters with indexes above the limit are
+// reported by an IndexLimitError. A negative value removes the limit.
+func MaxIndex(max int) Option {
+	return func(r *Reader) {
+		r.maxIndex = max
+	}
+}
+
+// DefaultValueTag is a functional option which allows to specify a custom struct tag for default
+// values (default: default). Default values are assigned to fields if the respective parameter is
+// missing. Default values of slice fields are separated by commas. An empty tag disables default
+// values.
+func DefaultValueTag(tag string) Option {
+	return func(r *Reader) {
+		r.config.DefaultTag = tag
+	}
+}
+
+// Layouts for times which are passed as number of seconds or milliseconds since January 1, 1970 UTC.
+// They can be used with the option TimeLayouts or the tag "layout", e.g. `layout:"unix"`.
+const (
+	Unix      = internal.UnixLayout
+	UnixMilli = internal.UnixMilliLayout
+)
+
+// TimeLayouts is a functional option which defines layouts for time.Time fields, which are tried in
+// order until the value can be parsed (default: none). Besides the layouts of the time package, the
+// layouts Unix and UnixMilli are supported. The layout of a single field can be specified by the tag
+// "layout", e.g. `layout:"2006-01-02"`. Fields without layout are parsed using the UnmarshalText method
+// of time.Time, which expects RFC 3339. Writers format times using the first layout.
+func TimeLayouts(layouts ...string) Option {
+	return func(r *Reader) {
+		r.config.TimeLayouts = layouts
+	}
+}
+
+// Location is a functional option which defines the time zone of time.Time values which are parsed
+// using a layout without time zone (default: UTC). Writers convert times into this time zone.
+func Location(loc *time.Location) Option {
+	return func(r *Reader) {
+		r.config.Location = loc
+	}
+}
+
+// CaseInsensitive is a functional option which enables case-insensitive matching of parameter names
+// (default: false), e.g. "Limit" and "LIMIT" are then read into the field for "limit". Parameters which
+// differ only in case are treated like a single parameter with the values of all of them. Map keys are
+// not affected and keep their case.
+func CaseInsensitive(caseInsensitive bool) Option {
+	return func(r *Reader) {
+		r.fold = caseInsensitive
+	}
+}
+
+// AliasConflicts is a functional option which defines whether the reader rejects parameters for the
+// same field under more than one name (default: false), e.g. "limit" and "per_page" for a field with the
+// tag `param:"limit,alias=per_page|pageSize"`. Conflicts are then reported by an AliasConflictError.
+// Otherwise the name of the field takes precedence over its aliases, which take precedence in the order
+// of the tag, and parameters with other names are ignored.
+func AliasConflicts(reject bool) Option {
+	return func(r *Reader) {
+		r.rejectAlias = reject
+	}
+}
+
+// MultiValuePolicy defines how multiple values of a parameter are handled if the target field accepts
+// a single value only, e.g. for "?sort=name&sort=age" and a string field.
+type MultiValuePolicy string
+
+// Policies for multiple values of single value fields.
+const (
+	// RejectMultiple reports a MultipleValuesError.
+	RejectMultiple MultiValuePolicy = internal.MultipleError
+	// FirstValue uses the first value and ignores all others.
+	FirstValue MultiValuePolicy = internal.MultipleFirst
+	// LastValue uses the last value and ignores all others.
+	LastValue MultiValuePolicy = internal.MultipleLast
+)
+
+// JoinValues returns a policy which joins multiple values of string fields using the separator. In the
+// tag "multiple" this policy is written as "join:" followed by the separator, e.g. `multiple:"join:,"`.
+func JoinValues(sep string) MultiValuePolicy {
+	return MultiValuePolicy(internal.MultipleJoin + ":" + sep)
+}
+
+// MultipleValues is a functional option which defines the policy for multiple values of parameters whose
+// target field accepts a single value only (default: RejectMultiple). The policy of a single field can be
+// specified by the tag "multiple", e.g. `multiple:"first"`. If the option is JoinValues, fields other than
+// strings keep the policy RejectMultiple. The policies don't apply to slices, which accept all values.
+func MultipleValues(policy MultiValuePolicy) Option {
+	return func(r *Reader) {
+		r.config.Multiple = string(policy)
+	}
+}
+
+// BoolMode defines which values are accepted for bool fields.
+type BoolMode int
+
+// Modes for parsing bool fields. The modes don't apply to types with custom parsers or types which
+// implement the TextUnmarshaler interface.
+const (
+	// StrictBool accepts the values of strconv.ParseBool, e.g. "true", "1", "false" or "0".
+	StrictBool BoolMode = iota
+	// LenientBool accepts also the words "yes", "y", "on", "no", "n" and "off" in any case. The value
+	// "on" is sent by checked HTML checkboxes without value attribute.
+	LenientBool
+	// PresenceBool is like LenientBool, but treats bool fields as flags: empty values (e.g. "?verbose")
+	// are parsed as true, and fields are set to false if the parameter is missing (e.g. an unchecked
+	// checkbox), regardless of their default value. Pointers, bool slices and maps are not affected by
+	// the latter, neither are fields of nil pointer structs.
+	PresenceBool
+)
+
+// Bools is a functional option which defines the mode for parsing bool fields (default: StrictBool).
+func Bools(mode BoolMode) Option {
+	return func(r *Reader) {
+		r.config.LenientBools = mode == LenientBool || mode == PresenceBool
+		r.config.PresenceBools = mode == PresenceBool
+	}
+}
+
+// CollectionFormat defines how multiple elements of a slice are represented by parameters.
+type CollectionFormat string
+
+// Collection formats supported by readers and writers. The formats are named after the collection
+// formats of OpenAPI 2. Elements of formats with delimiter can be enclosed in double quotes, in order to
+// contain the delimiter. A double quote inside such an element is escaped by a second double quote.
+const (
+	// Multi passes each element as a separate value: ?id=1&id=2
+	Multi CollectionFormat = "multi"
+	// CSV separates elements by commas: ?id=1,2
+	CSV CollectionFormat = "csv"
+	// SSV separates elements by spaces: ?id=1%202
+	SSV CollectionFormat = "ssv"
+	// TSV separates elements by tabs: ?id=1%092
+	TSV CollectionFormat = "tsv"
+	// Pipes separates elements by pipes: ?id=1|2
+	Pipes CollectionFormat = "pipes"
+)
+
+// Collection is a functional option which defines the collection format of all slice fields
+// (default: multi), except slices of structs and uploaded files. The format of a single field can be
+// specified by adding the name of the format as an option to the field tag, e.g. `param:"ids,csv"`.
+// Regardless of the format, elements can always be passed in multiple values.
+func Collection(format CollectionFormat) Option {
+	return func(r *Reader) {
+		r.config.Collection = string(format)
 	}
 }
 
@@ -49,19 +229,82 @@ func Strict(strict bool) Option {
 	}
 }
 
+// WithParser is a functional option which registers a custom parser for fields of the provided type
+// (or pointers and slices of this type). The parse function must return a value which is assignable
+// or convertible to the type. Custom parsers only apply to the reader they are registered for and
+// take precedence over the built-in parsers, e.g. they can be used for types implementing the
+// TextUnmarshaler interface as well. Parsers for a specific type take precedence over parsers
+// registered with WithKindParser or WithMatchParser.
+func WithParser(t reflect.Type, parse func(string) (interface{}, error)) Option {
+	return func(r *Reader) {
+		if r.config.TypeParsers == nil {
+			r.config.TypeParsers = make(map[reflect.Type]internal.Parser)
+		}
+		r.config.TypeParsers[t] = internal.FuncParser{
+			Match: func(other reflect.Type) bool { return other == t },
+			Func:  func(_ reflect.Type, s string) (interface{}, error) { return parse(s) },
+		}
+	}
+}
+
+// WithKindParser is a functional option which registers a custom parser for fields of all types with
+// the provided kind. See WithMatchParser for details.
+func WithKindParser(kind reflect.Kind, parse func(string) (interface{}, error)) Option {
+	return func(r *Reader) {
+		r.config.Parsers = append(r.config.Parsers, internal.FuncParser{
+			Match: func(t reflect.Type) bool { return t.Kind() == kind },
+			Func:  func(_ reflect.Type, s string) (interface{}, error) { return parse(s) },
+		})
+	}
+}
+
+// WithMatchParser is a functional option which registers a custom parser for fields of all types
+// accepted by the match function. The parse function receives the matched type and must return a
+// value which is assignable or convertible to this type. If more than one parser registered with
+// WithKindParser or WithMatchParser matches a type, the parser registered first is used.
+func WithMatchParser(match func(reflect.Type) bool, parse func(reflect.Type, string) (interface{}, error)) Option {
+	return func(r *Reader) {
+		r.config.Parsers = append(r.config.Parsers, internal.FuncParser{Match: match, Func: parse})
+	}
+}
+
 // Reader defines methods which can read query parameters and assign them to matching
 // fields of target structs.
+//
+// A reader caches information about the fields of each target type it encounters. It is
+// therefore recommended to create a reader once and to reuse it. Readers are safe for
+// concurrent use.
 type Reader struct {
-	tag    string
-	strict bool
-	mapper func(string) string
+	config      internal.Config
+	strict      bool
+	maxIndex    int
+	source      RequestSource
+	maxBodySize int64
+	pathValues  func(*http.Request) Source
+	validator   func(interface{}) error
+	fold        bool
+	rejectAlias bool
+	plans       sync.Map
 }
 
 // NewReader creates a new reader which can be configured with predefined functional options. The options
 // can be used to configure the following reader behaviour: custom field name mapping (default: lower
-// case), custom field tag (default: param) and strict mode (default: false)
+// case), custom field tag (default: param), custom default value tag (default: default), collection
+// format (default: multi), strict mode (default: false) and custom parsers (default: none)
 func NewReader(options ...Option) *Reader {
-	r := &Reader{tag: defaultTag, mapper: defaultMapper}
+	r := &Reader{maxIndex: defaultMaxIndex, source: Form, maxBodySize: defaultMaxBodySize,
+		pathValues: defaultPathValues, config: internal.Config{
+			Tag:        defaultTag,
+			Mapper:     defaultMapper,
+			Syntax:     internal.Syntax{Separator: defaultSeparator},
+			DefaultTag: defaultValueTag,
+			LayoutTag:  defaultLayoutTag,
+			Sources: []internal.NamedSource{
+				{Name: headerSource, Tag: headerSource, Normalize: http.CanonicalHeaderKey},
+				{Name: cookieSource, Tag: cookieSource},
+				{Name: pathSource, Tag: pathSource},
+			},
+		}}
 
 	for _, opt := range options {
 		opt(r)
@@ -75,114 +318,742 @@ func NewReader(options ...Option) *Reader {
 //
 // If an error occurs while parsing the values for struct fields, the returned error probably
 // implements the interface MultiError. In that case specific errors for each failed field
-// can be obtained from the error.
+// can be obtained from the error. Those errors are of the types ParseError, MissingParameterError,
+// UnknownParameterError, MultipleValuesError, AliasConflictError, UnsupportedTypeError,
+// IndexLimitError, ConstraintError or ValidationError. Invalid targets or invalid struct tags (e.g. default values
+// which can't be parsed) are reported by errors which don't implement MultiError.
+//
+// Fields with the tags "header", "cookie" and "path" are ignored by Read, since there are no such
+// values (see ReadRequest).
+//
+// If all parameters were read successfully, the targets are validated: the Validate methods
+// (func() error) of nested structs and slice elements are called bottom-up, followed by the
+// Validate methods of the targets and the function registered with the option Validator.
 func (r *Reader) Read(params url.Values, targets ...interface{}) error {
-	var processed map[string]struct{}
-	if r.strict {
-		processed = make(map[string]struct{})
+	_, err := r.read(r.newState(params, nil, nil), targets, false)
+	return err
+}
+
+// newState creates the state for reading the parameters, the values of other sources (e.g. http headers)
+// and uploaded files. Sources are indexed by their name.
+func (r *Reader) newState(params url.Values, sources map[string]Source,
+	files map[string][]*multipart.FileHeader) *readState {
+	state := &readState{params: r.normalize(params), sources: sources, files: r.normalizeFiles(files),
+		errors: multiError{}}
+	if r.fold {
+		state.folded = foldNames(state.params)
+		state.foldedFiles = foldNames(state.files)
+	}
+	return state
+}
+
+// read assigns the parameters, the values of other sources and uploaded files of the state to the fields
+// of the targets. Consumed parameters and assigned fields are only tracked in strict mode or if withResult
+// is true, otherwise the returned result only contains warnings.
+func (r *Reader) read(state *readState, targets []interface{}, withResult bool) (*Result, error) {
+	if r.strict || withResult {
+		state.processed = make(map[string]struct{})
 	}
 
-	fieldErrors := multiError{}
+	values := make([]reflect.Value, 0, len(targets))
+	plans := make([]*internal.Plan, 0, len(targets))
 	for _, target := range targets {
 		targetVal := reflect.ValueOf(target)
 		if targetVal.Kind() != reflect.Ptr {
-			return errors.New("target must be a pointer")
+			return &Result{}, errors.New("target must be a pointer")
 		}
 
 		targetVal = targetVal.Elem()
 		if targetVal.Kind() != reflect.Struct {
-			return errors.New("target must be a struct")
+			return &Result{}, errors.New("target must be a struct")
 		}
 
-		it := internal.NewIterator(targetVal, r.tag, r.mapper)
-		for it.HasNext() {
-			name, field := it.Next()
-			if values, ok := params[name]; ok && len(values) > 0 {
-				var err error
+		plan, err := r.plan(targetVal.Type())
+		if err != nil {
+			return &Result{}, err
+		}
 
-				if field.Kind() == reflect.Slice {
-					err = r.readSlice(values, field)
-				} else {
-					err = r.readSingle(values, field, it)
-				}
+		r.readStruct(state, "", "", targetVal, plan)
+		values = append(values, targetVal)
+		plans = append(plans, plan)
+	}
 
-				if err != nil {
-					fieldErrors[name] = err
-				}
+	var unconsumed []string
+	if state.processed != nil {
+		unconsumed = state.unconsumed()
+	}
+	if r.strict {
+		for _, name := range unconsumed {
+			state.errors[name] = &UnknownParameterError{Param: name}
+		}
+	}
 
-				if r.strict {
-					processed[name] = struct{}{}
-				}
+	// targets are only validated if all parameters were read successfully
+	if len(state.errors) == 0 {
+		for i, target := range targets {
+			r.validate(state, "", values[i], plans[i])
+			if r.validator != nil {
+				r.addValidationError(state, "", r.targetName("", values[i]), "", plans[i], r.validator(target))
 			}
 		}
 	}
 
-	if r.strict {
-		for name := range params {
-			if _, ok := processed[name]; !ok {
-				fieldErrors[name] = errors.New("unknown parameter name")
-			}
+	result := &Result{Warnings: state.warnings}
+	if withResult {
+		result.Assigned, result.Unconsumed = state.assigned, unconsumed
+		for name := range state.processed {
+			result.Consumed = append(result.Consumed, name)
 		}
+		sort.Strings(result.Consumed)
+		sort.Strings(result.Assigned)
+	}
+	if len(state.errors) > 0 {
+		return result, state.errors
+	}
+
+	return result, nil
+}
+
+// plan returns the cached plan for the provided struct type or compiles a new one.
+func (r *Reader) plan(t reflect.Type) (*internal.Plan, error) {
+	if plan, ok := r.plans.Load(t); ok {
+		return plan.(*internal.Plan), nil
+	}
+
+	compiled, err := internal.Compile(t, &r.config)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, _ := r.plans.LoadOrStore(t, compiled)
+	return plan.(*internal.Plan), nil
+}
+
+// normalize converts all parameter names into the canonical form of the configured syntax.
+func (r *Reader) normalize(params url.Values) url.Values {
+	if !r.config.Syntax.Brackets {
+		return params
+	}
+
+	normalized := make(url.Values, len(params))
+	for name, values := range params {
+		name = r.config.Syntax.Normalize(name)
+		normalized[name] = append(normalized[name], values...)
+	}
+	return normalized
+}
+
+// normalizeFiles converts the names of uploaded files like the names of parameters (see normalize).
+func (r *Reader) normalizeFiles(files map[string][]*multipart.FileHeader) map[string][]*multipart.FileHeader {
+	if !r.config.Syntax.Brackets || files == nil {
+		return files
 	}
 
-	if len(fieldErrors) > 0 {
-		return fieldErrors
+	normalized := make(map[string][]*multipart.FileHeader, len(files))
+	for name, headers := range files {
+		name = r.config.Syntax.Normalize(name)
+		normalized[name] = append(normalized[name], headers...)
 	}
+	return normalized
+}
+
+// readState holds the parameters and the intermediate results of a single call to Read. Parameters
+// which are missing in params are looked up in lookup if present, see ReadSource.
+type readState struct {
+	params      url.Values
+	lookup      Source
+	sources     map[string]Source
+	files       map[string][]*multipart.FileHeader
+	errors      multiError
+	processed   map[string]struct{} // nil if consumed parameters and assigned fields are not tracked
+	folded      map[string][]string
+	foldedFiles map[string][]string
+	warnings    []Warning
+	assigned    []string
+}
+
+// foldNames indexes names by their lower case form, names with the same lower case form are sorted.
+func foldNames[V any](values map[string]V) map[string][]string {
+	folded := make(map[string][]string, len(values))
+	for name := range values {
+		lower := strings.ToLower(name)
+		folded[lower] = append(folded[lower], name)
+	}
+	for _, names := range folded {
+		sort.Strings(names)
+	}
+	return folded
+}
 
+// names returns the names of the parameters which match the provided name. If names are matched
+// case-insensitively, there can be more than one such parameter (e.g. "limit" and "LIMIT").
+func (state *readState) names(name string) []string {
+	if state.folded != nil {
+		if names := state.folded[strings.ToLower(name)]; len(names) > 0 || state.lookup == nil {
+			return names
+		}
+	} else if _, ok := state.params[name]; ok {
+		return []string{name}
+	}
+	if state.lookup != nil {
+		if values, ok := state.lookup.Lookup(name); ok {
+			state.params[name] = values
+			return []string{name}
+		}
+	}
 	return nil
 }
 
-func (r *Reader) readSingle(values []string, field reflect.Value, it *internal.Iterator) error {
-	if len(values) > 1 {
-		return errors.New("multiple values for single value parameter")
+// values returns the values of all parameters which match the provided name. The returned slice must not
+// be modified.
+func (state *readState) values(name string) []string {
+	if state.folded == nil && state.lookup == nil {
+		return state.params[name]
 	}
 
-	// create empty field elements
-	if field.Kind() == reflect.Ptr {
-		if field.IsNil() {
-			field.Set(reflect.New(field.Type().Elem()))
+	var values []string
+	for _, param := range state.names(name) {
+		values = append(values, state.params[param]...)
+	}
+	return values
+}
+
+// take returns the values of all parameters which match the provided name and marks the parameters
+// as processed.
+func (state *readState) take(name string) []string {
+	if state.folded == nil && state.lookup == nil {
+		values := state.params[name]
+		if len(values) > 0 {
+			state.consume(name)
 		}
-		field = field.Elem()
+		return values
 	}
 
-	parser, ok := internal.FindParser(field)
-	if !ok {
-		return errors.New("target field type is not supported")
+	values := state.values(name)
+	if len(values) > 0 {
+		for _, param := range state.names(name) {
+			state.consume(param)
+		}
+	}
+	return values
+}
+
+// fileNames returns the names of the uploaded files which match the provided name.
+func (state *readState) fileNames(name string) []string {
+	if state.foldedFiles != nil {
+		return state.foldedFiles[strings.ToLower(name)]
 	}
-	if field.Kind() == reflect.Struct {
-		it.SkipStruct()
+	if _, ok := state.files[name]; ok {
+		return []string{name}
 	}
+	return nil
+}
 
-	err := parser.Parse(field, values[0])
-	return err
+// match replaces the beginning of the parameter name by the provided prefix if both only differ in
+// case and names are matched case-insensitively. Thereby names of nested parameters (e.g. "Items.0.Name")
+// can be compared with the paths of fields.
+func (state *readState) match(param, prefix string) string {
+	if state.folded == nil || len(param) < len(prefix) || !strings.EqualFold(param[:len(prefix)], prefix) {
+		return param
+	}
+	return prefix + param[len(prefix):]
 }
 
-func (r *Reader) readSlice(values []string, slice reflect.Value) error {
-	slice.Set(reflect.MakeSlice(slice.Type(), len(values), len(values)))
+// consume marks the parameter as processed
+func (state *readState) consume(name string) {
+	if state.processed != nil {
+		state.processed[name] = struct{}{}
+	}
+}
 
-	first := slice.Index(0)
-	isPtr := first.Kind() == reflect.Ptr
-	if isPtr {
-		for i := 0; i < slice.Len(); i++ {
-			elem := slice.Index(i)
-			if elem.IsNil() {
-				slice.Index(i).Set(reflect.New(slice.Index(i).Type().Elem()))
+// assign records that a field was assigned a value from the parameters
+func (state *readState) assign(name string) {
+	if state.processed != nil {
+		state.assigned = append(state.assigned, name)
+	}
+}
+
+// unconsumed returns the sorted names of all parameters and uploaded files which were not processed
+func (state *readState) unconsumed() []string {
+	var names []string
+	for name := range state.params {
+		if _, ok := state.processed[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for name := range state.files {
+		if _, ok := state.processed[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readStruct reads the parameters into the fields of the target struct. The names of the
+// parameters are prefixed if the target is an element of a slice. The canonical prefix doesn't
+// contain aliases and is used to record assigned fields (see Result.Assigned).
+func (r *Reader) readStruct(state *readState, prefix, canonical string, target reflect.Value,
+	plan *internal.Plan) {
+	for i := range plan.Fields {
+		field := &plan.Fields[i]
+		if field.Source != "" {
+			r.readSource(state, target, field)
+			continue
+		}
+
+		path := r.config.Syntax.Join(canonical, field.Path)
+		name := r.config.Syntax.Join(prefix, field.Path)
+		if len(field.Aliases) > 0 || len(field.Deprecated) > 0 {
+			var ok bool
+			name, ok = r.resolveName(state, prefix, name, field)
+			if !ok {
+				continue
 			}
 		}
-		first = first.Elem()
+		if field.File {
+			if r.readFile(state, name, target, field) {
+				state.assign(path)
+			}
+			continue
+		}
+		if field.Map {
+			if r.readMap(state, name, target, field) {
+				state.assign(path)
+			}
+			continue
+		}
+
+		values := state.take(name)
+		present := len(values) > 0
+
+		if field.Slice {
+			indexed, assigned := r.readIndexed(state, name, path, target, field)
+			if assigned {
+				state.assign(path)
+			}
+			if indexed {
+				continue
+			}
+		}
+
+		if !present {
+			if field.Required {
+				state.errors[name] = &MissingParameterError{Param: name}
+			}
+			values = missingValues(field)
+			if values == nil {
+				continue
+			}
+		}
+
+		// missing flags don't allocate nil parent structs
+		value, ok := field.Value(target, present || !field.Flag)
+		if !ok {
+			continue
+		}
+		err := r.readValue(name, values, value, field)
+		if err != nil {
+			state.errors[name] = err
+		} else if present {
+			state.assign(path)
+		}
+	}
+}
+
+// readSource reads a field from a source other than the parameters, e.g. http headers or cookies.
+// Errors are reported with the name of the source as prefix, e.g. "header:X-Request-Id". Fields are
+// skipped if their source is not supplied, e.g. headers in case of Read.
+func (r *Reader) readSource(state *readState, target reflect.Value, field *internal.Field) {
+	source := state.sources[field.Source]
+	if source == nil {
+		return
 	}
 
-	parser, ok := internal.FindParser(first)
+	name := field.Source + ":" + field.Path
+	values, _ := source.Lookup(field.Path)
+	present := len(values) > 0
+	if present && state.lookup != nil && field.Source == pathSource {
+		// ReadSource reads the parameters and the path values from the same source
+		state.consume(field.Path)
+	}
+	if !present {
+		if field.Required {
+			state.errors[name] = &MissingParameterError{Param: name}
+		}
+		values = missingValues(field)
+		if values == nil {
+			return
+		}
+	}
+
+	value, ok := field.Value(target, present || !field.Flag)
 	if !ok {
-		return errors.New("target field type is not supported")
+		return
+	}
+	err := r.readValue(name, values, value, field)
+	if err != nil {
+		state.errors[name] = err
+	} else if present {
+		state.assign(name)
+	}
+}
+
+// resolveName determines the name which is used to read a field with aliases or deprecated names: the
+// first name of the field which is present in the parameters, starting with the name itself followed by
+// the aliases. Parameters with other names of the field are ignored. If the reader rejects alias conflicts
+// and parameters with more than one name are present, an AliasConflictError is reported and the returned
+// value is false. Each parameter with a deprecated name is reported by a warning, the replacement is the
+// first name of the field which is not deprecated.
+func (r *Reader) resolveName(state *readState, prefix, name string, field *internal.Field) (string, bool) {
+	paths := append([]string{field.Path}, field.Aliases...)
+
+	replacement := ""
+	for _, path := range paths {
+		if !field.IsDeprecated(path) {
+			replacement = r.config.Syntax.Join(prefix, path)
+			break
+		}
+	}
+
+	var present []string
+	for _, path := range paths {
+		n := r.config.Syntax.Join(prefix, path)
+		params := r.paramNames(state, n, field)
+		if len(params) == 0 {
+			continue
+		}
+
+		present = append(present, n)
+		if field.IsDeprecated(path) {
+			sort.Strings(params)
+			for _, param := range params {
+				warning := Warning{Param: param}
+				if replacement != "" {
+					// keep keys of maps and indexes of slices, e.g. "f.status" is replaced by "filter.status"
+					warning.Replacement = replacement + param[len(n):]
+				}
+				state.warnings = append(state.warnings, warning)
+			}
+		}
+	}
+	if len(present) == 0 {
+		return name, true
 	}
 
+	// parameters of the name which is read are consumed while reading, which doesn't happen on conflicts
+	conflict := len(present) > 1 && r.rejectAlias
+	ignored := present[1:]
+	if conflict {
+		ignored = present
+	}
+	for _, other := range ignored {
+		for _, param := range r.paramNames(state, other, field) {
+			state.consume(param)
+		}
+	}
+
+	if conflict {
+		state.errors[name] = &AliasConflictError{Param: name, Names: present}
+		return name, false
+	}
+	return present[0], true
+}
+
+// paramNames returns the names of all parameters (or uploaded files) which are read into the field if
+// the field is read using the provided name, including parameters for map entries and slice indexes.
+func (r *Reader) paramNames(state *readState, name string, field *internal.Field) []string {
+	if field.File {
+		return state.fileNames(name)
+	}
+
+	var names []string
+	for _, param := range state.names(name) {
+		if len(state.params[param]) > 0 {
+			names = append(names, param)
+		}
+	}
+	if !field.Map && !field.Slice {
+		return names
+	}
+
+	for param, values := range state.params {
+		if len(values) == 0 {
+			continue
+		}
+		matched := state.match(param, name)
+		if _, ok := r.config.Syntax.Key(matched, name); ok && field.Map {
+			names = append(names, param)
+		} else if _, _, ok := r.config.Syntax.Index(matched, name); ok && field.Slice {
+			names = append(names, param)
+		}
+	}
+	return names
+}
+
+// readFile assigns the uploaded files with the provided name to the field. Files which exceed the maximum
+// size or have a content type which is not accepted by the field are reported by a ConstraintError. The
+// returned value is true if files were assigned.
+func (r *Reader) readFile(state *readState, name string, target reflect.Value, field *internal.Field) bool {
+	var files []*multipart.FileHeader
+	for _, fileName := range state.fileNames(name) {
+		state.consume(fileName)
+		files = append(files, state.files[fileName]...)
+	}
+	if len(files) == 0 {
+		if field.Required {
+			state.errors[name] = &MissingParameterError{Param: name}
+		}
+		return false
+	}
+
+	switch {
+	case field.Slice || len(files) == 1:
+	case field.Multiple == internal.MultipleFirst:
+		files = files[:1]
+	case field.Multiple == internal.MultipleLast:
+		files = files[len(files)-1:]
+	default:
+		filenames := make([]string, 0, len(files))
+		for _, file := range files {
+			filenames = append(filenames, file.Filename)
+		}
+		state.errors[name] = &MultipleValuesError{Param: name, Values: filenames}
+		return false
+	}
+
+	if field.MaxItems > 0 && len(files) > field.MaxItems {
+		state.errors[name] = maxItemsError(name, len(files), field)
+		return false
+	}
+
+	for _, file := range files {
+		if field.MaxSize > 0 && file.Size > field.MaxSize {
+			state.errors[name] = &ConstraintError{Param: name, Value: file.Filename, Constraint: "maxsize",
+				Limit: field.Tag.Get("maxsize")}
+			return false
+		}
+		if !field.Accepts(file.Header.Get("Content-Type")) {
+			state.errors[name] = &ConstraintError{Param: name, Value: file.Filename, Constraint: "accept",
+				Limit: field.Tag.Get("accept")}
+			return false
+		}
+	}
+
+	value, _ := field.Value(target, true)
+	if field.Slice {
+		value.Set(reflect.ValueOf(append([]*multipart.FileHeader(nil), files...)))
+	} else {
+		value.Set(reflect.ValueOf(files[0]))
+	}
+	return true
+}
+
+// readIndexed reads parameters of slice elements which are addressed by their index, e.g.
+// "items.0.name" or "ids[1]". Elements are ordered by their index, gaps between indexes are
+// removed. The first returned value is false if there are no indexed parameters for the field,
+// the second is true if the slice was assigned. Fields of struct elements are recorded as assigned
+// with the path of the slice field.
+func (r *Reader) readIndexed(state *readState, name, path string, target reflect.Value,
+	field *internal.Field) (bool, bool) {
+	var indexes []int
+	seen := make(map[int]bool)
+	for param, paramValues := range state.params {
+		index, exact, ok := r.config.Syntax.Index(state.match(param, name), name)
+		if !ok || len(paramValues) == 0 {
+			continue
+		}
+
+		if field.Elem == nil {
+			if !exact {
+				continue
+			}
+			state.consume(param)
+		}
+
+		if r.maxIndex >= 0 && index > r.maxIndex {
+			state.consume(param)
+			state.errors[param] = &IndexLimitError{Param: param, Index: index, Max: r.maxIndex}
+			continue
+		}
+
+		if !seen[index] {
+			seen[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+
+	if len(indexes) == 0 {
+		return false, false
+	}
+	sort.Ints(indexes)
+
+	slice, _ := field.Value(target, true)
+	if field.Elem == nil {
+		values := append([]string(nil), state.values(name)...)
+		for _, index := range indexes {
+			values = append(values, state.values(r.config.Syntax.Join(name, strconv.Itoa(index)))...)
+		}
+
+		err := r.readValue(name, values, slice, field)
+		if err != nil {
+			state.errors[name] = err
+			return true, false
+		}
+		return true, true
+	}
+
+	if field.MaxItems > 0 && len(indexes) > field.MaxItems {
+		for param := range state.params {
+			if _, _, ok := r.config.Syntax.Index(state.match(param, name), name); ok {
+				state.consume(param)
+			}
+		}
+		state.errors[name] = maxItemsError(name, len(indexes), field)
+		return true, false
+	}
+
+	slice.Set(reflect.MakeSlice(slice.Type(), len(indexes), len(indexes)))
+	for i, index := range indexes {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elem.Type().Elem()))
+			elem = elem.Elem()
+		}
+		idx := strconv.Itoa(index)
+		r.readStruct(state, r.config.Syntax.Join(name, idx), r.config.Syntax.Join(path, idx), elem, field.Elem)
+	}
+	return true, true
+}
+
+// readMap reads all parameters which refer to entries of the map field, e.g. "name.key" or "name[key]".
+// The returned value is true if at least one entry was assigned.
+func (r *Reader) readMap(state *readState, name string, target reflect.Value, field *internal.Field) bool {
+	var m reflect.Value
+	assigned := false
+	for param, values := range state.params {
+		key, ok := r.config.Syntax.Key(state.match(param, name), name)
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		state.consume(param)
+
+		if field.KeyParser == nil || field.Parser == nil {
+			state.errors[param] = &UnsupportedTypeError{Param: param, Type: field.Type}
+			continue
+		}
+
+		if !m.IsValid() {
+			m, _ = field.Value(target, true)
+			if m.IsNil() {
+				m.Set(reflect.MakeMap(m.Type()))
+			}
+		}
+
+		keyVal := reflect.New(m.Type().Key()).Elem()
+		err := field.KeyParser.Parse(keyVal, key)
+		if err != nil {
+			state.errors[param] = &ParseError{Param: param, Value: key, TargetType: keyVal.Type(), Cause: err}
+			continue
+		}
+
+		elem := reflect.New(m.Type().Elem()).Elem()
+		err = r.readValue(param, values, elem, field)
+		if err != nil {
+			state.errors[param] = err
+			continue
+		}
+
+		m.SetMapIndex(keyVal, elem)
+		assigned = true
+	}
+	return assigned
+}
+
+// missingValues returns the values which are used if the parameter of the field is missing: false for
+// flags, otherwise the default values (nil if there are none).
+func missingValues(field *internal.Field) []string {
+	if field.Flag {
+		return []string{"false"}
+	}
+	return field.Default
+}
+
+// readValue parses the values and assigns them to the provided value of the field.
+func (r *Reader) readValue(name string, values []string, value reflect.Value, field *internal.Field) error {
+	if field.Slice {
+		return r.readSlice(name, values, value, field)
+	}
+	return r.readSingle(name, values, value, field)
+}
+
+func (r *Reader) readSingle(name string, values []string, value reflect.Value, field *internal.Field) error {
+	if len(values) > 1 {
+		switch field.Multiple {
+		case internal.MultipleFirst:
+			values = values[:1]
+		case internal.MultipleLast:
+			values = values[len(values)-1:]
+		case internal.MultipleJoin:
+			values = []string{strings.Join(values, field.JoinSeparator)}
+		default:
+			return &MultipleValuesError{Param: name, Values: values}
+		}
+	}
+
+	if field.Parser == nil {
+		return &UnsupportedTypeError{Param: name, Type: field.Type}
+	}
+
+	// create empty field elements
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	err := field.Parser.Parse(value, values[0])
+	if err != nil {
+		return &ParseError{Param: name, Value: values[0], TargetType: value.Type(), Cause: err}
+	}
+	return checkConstraints(name, values[0], value, field)
+}
+
+func (r *Reader) readSlice(name string, values []string, slice reflect.Value, field *internal.Field) error {
+	if field.Parser == nil {
+		return &UnsupportedTypeError{Param: name, Type: field.Type}
+	}
+
+	elements := make([]string, 0, len(values))
+	for _, value := range values {
+		split, err := internal.Split([]string{value}, field.Delimiter)
+		if err != nil {
+			return &ParseError{Param: name, Value: value, TargetType: slice.Type(), Cause: err}
+		}
+		elements = append(elements, split...)
+	}
+	values = elements
+
+	if field.MaxItems > 0 && len(values) > field.MaxItems {
+		return maxItemsError(name, len(values), field)
+	}
+
+	slice.Set(reflect.MakeSlice(slice.Type(), len(values), len(values)))
+
+	isPtr := slice.Type().Elem().Kind() == reflect.Ptr
 	for i, value := range values {
 		elem := slice.Index(i)
 		if isPtr {
+			elem.Set(reflect.New(elem.Type().Elem()))
 			elem = elem.Elem()
 		}
-		err := parser.Parse(elem, value)
+		err := field.Parser.Parse(elem, value)
+		if err != nil {
+			return &ParseError{Param: name, Value: value, TargetType: elem.Type(), Cause: err}
+		}
+		err = checkConstraints(name, value, elem, field)
 		if err != nil {
 			return err
 		}
@@ -190,6 +1061,23 @@ func (r *Reader) readSlice(values []string, slice reflect.Value) error {
 	return nil
 }
 
+// checkConstraints checks whether the parsed value satisfies all constraints of the field.
+func checkConstraints(name, raw string, value reflect.Value, field *internal.Field) error {
+	for i := range field.Constraints {
+		constraint := &field.Constraints[i]
+		if !constraint.Check(value, raw) {
+			return &ConstraintError{Param: name, Value: raw, Constraint: constraint.Name, Limit: constraint.Limit}
+		}
+	}
+	return nil
+}
+
+// maxItemsError creates the error for slices with too many elements
+func maxItemsError(name string, items int, field *internal.Field) error {
+	return &ConstraintError{Param: name, Value: strconv.Itoa(items), Constraint: "maxitems",
+		Limit: strconv.Itoa(field.MaxItems)}
+}
+
 // MultiError is an error which also contains a map of additional (named) errors
 // which altogether caused the actual failure.
 type MultiError interface {
@@ -259,3 +1147,19 @@ func (err multiError) Format(s fmt.State, verb rune) {
 func (err multiError) ErrorMap() map[string]error {
 	return err
 }
+
+// Unwrap returns all errors ordered by field name, which allows to inspect them using
+// the functions errors.Is and errors.As from the standard library.
+func (err multiError) Unwrap() []error {
+	keys := make([]string, 0, len(err))
+	for k := range err {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	errs := make([]error, 0, len(keys))
+	for _, k := range keys {
+		errs = append(errs, err[k])
+	}
+	return errs
+}
@@ -5,7 +5,11 @@ package qparam_test
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
 
 	"github.com/stoewer/go-qparam"
 	"github.com/stoewer/go-strcase"
@@ -90,3 +94,78 @@ func Example_mapper() {
 	fmt.Println(info.SessionID)
 	// Output: abcdefghijklmn
 }
+
+func Example_writer() {
+	type Page struct {
+		Limit  int
+		Offset int
+	}
+
+	type Filters struct {
+		Name string
+		Tags []string
+	}
+
+	page := Page{Limit: 25, Offset: 100}
+	filters := Filters{Name: "Doe", Tags: []string{"a", "b"}}
+
+	writer := qparam.NewWriter()
+	values, _ := writer.Write(&page, &filters)
+
+	fmt.Println(values.Encode())
+	// Output: limit=25&name=Doe&offset=100&tags=a&tags=b
+}
+
+func ExampleDecode() {
+	type Page struct {
+		Limit  int
+		Offset int
+	}
+
+	values := url.Values{"limit": []string{"25"}, "offset": []string{"100"}}
+
+	reader := qparam.NewReader()
+	page, _ := qparam.Decode[Page](reader, values)
+
+	fmt.Printf("%d %d", page.Limit, page.Offset)
+	// Output: 25 100
+}
+
+func Example_request() {
+	type Search struct {
+		Query string `param:"q"`
+		Limit int
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/search?q=foo&limit=10", strings.NewReader("q=bar"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var search Search
+	reader := qparam.NewReader()
+	reader.ReadRequest(req, &search)
+
+	fmt.Printf("%s %d", search.Query, search.Limit)
+	// Output: bar 10
+}
+
+func Example_parser() {
+	type Color int32
+
+	colors := map[string]Color{"red": 1, "green": 2, "blue": 3}
+	values := url.Values{"color": []string{"green"}}
+	info := struct {
+		Color Color
+	}{}
+
+	reader := qparam.NewReader(qparam.WithParser(reflect.TypeOf(Color(0)), func(s string) (interface{}, error) {
+		color, ok := colors[s]
+		if !ok {
+			return nil, fmt.Errorf("unknown color %q", s)
+		}
+		return color, nil
+	}))
+	_ = reader.Read(values, &info)
+
+	fmt.Println(info.Color)
+	// Output: 2
+}
@@ -4,7 +4,10 @@
 package qparam_test
 
 import (
+	"errors"
 	"net/url"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -192,6 +195,860 @@ func TestReader_Read(t *testing.T) {
 		assert.EqualValues(t, stringExpected, stringsTarget)
 	})
 
+	t.Run("custom parsers", func(t *testing.T) {
+		type status int32
+		type code string
+
+		type custom struct {
+			Status   status
+			Statuses []*status
+			Code     code
+			Time     time.Time
+			Name     string
+		}
+
+		statusValues := map[string]status{"open": 1, "closed": 2}
+		open, closed := status(1), status(2)
+		expected := custom{Status: 2, Statuses: []*status{&open, &closed}, Code: "X-abc", Time: now, Name: "Doe"}
+		values := url.Values{
+			"status":   []string{"closed"},
+			"statuses": []string{"open", "closed"},
+			"code":     []string{"abc"},
+			"time":     []string{"now"},
+			"name":     []string{"Doe"},
+		}
+
+		reader := qparam.NewReader(
+			qparam.WithParser(reflect.TypeOf(status(0)), func(s string) (interface{}, error) {
+				if v, ok := statusValues[s]; ok {
+					return v, nil
+				}
+				return nil, errors.New("unknown status")
+			}),
+			qparam.WithParser(reflect.TypeOf(time.Time{}), func(s string) (interface{}, error) {
+				return now, nil
+			}),
+			qparam.WithKindParser(reflect.Int32, func(s string) (interface{}, error) {
+				return nil, errors.New("type parsers take precedence")
+			}),
+			qparam.WithMatchParser(
+				func(t reflect.Type) bool { return t == reflect.TypeOf(code("")) },
+				func(t reflect.Type, s string) (interface{}, error) { return "X-" + s, nil },
+			),
+		)
+		target := custom{}
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, target)
+
+		err = reader.Read(url.Values{"status": []string{"unknown"}}, &target)
+		assert.EqualError(t, err, "an error occurred while reading the parameter status")
+
+		err = qparam.NewReader().Read(url.Values{"time": []string{"now"}}, &target)
+		assert.Error(t, err, "custom parsers must not leak into other readers")
+	})
+
+	t.Run("default values", func(t *testing.T) {
+		type page struct {
+			Limit  int    `default:"25"`
+			Offset *int   `default:"0"`
+			Sort   string `default:"name"`
+		}
+
+		type defaults struct {
+			Page     *page
+			Tags     []string `default:"a,b"`
+			Numbers  []*int   `default:"1,2"`
+			Time     time.Time
+			Override string `default:"default" param:"override"`
+		}
+
+		zero, one, two := 0, 1, 2
+		expected := defaults{
+			Page:     &page{Limit: 10, Offset: &zero, Sort: "name"},
+			Tags:     []string{"a", "b"},
+			Numbers:  []*int{&one, &two},
+			Override: "value",
+		}
+		values := url.Values{"page.limit": []string{"10"}, "override": []string{"value"}}
+
+		target := defaults{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, target)
+	})
+
+	t.Run("default value tag", func(t *testing.T) {
+		target := struct {
+			Limit  int `def:"25"`
+			Offset int `default:"100"`
+		}{}
+
+		reader := qparam.NewReader(qparam.DefaultValueTag("def"))
+		err := reader.Read(url.Values{}, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 25, target.Limit)
+		assert.Equal(t, 0, target.Offset)
+	})
+
+	t.Run("invalid default values", func(t *testing.T) {
+		target := struct {
+			Limit int `default:"twenty"`
+		}{}
+
+		reader := qparam.NewReader()
+		err := reader.Read(url.Values{"limit": []string{"10"}}, &target)
+
+		assert.Error(t, err)
+		_, ok := err.(qparam.MultiError)
+		assert.False(t, ok, "configuration errors must not be a MultiError")
+		assert.Contains(t, err.Error(), "invalid default value for field Limit")
+	})
+
+	t.Run("required parameters", func(t *testing.T) {
+		type filter struct {
+			Name string `param:",required"`
+			Age  int
+		}
+
+		type required struct {
+			Limit  int `param:"limit,required"`
+			Offset int `param:"offset"`
+			Filter *filter
+		}
+
+		values := url.Values{"offset": []string{"0"}, "filter.age": []string{"31"}, "limit": []string{}}
+
+		target := required{}
+		reader := qparam.NewReader()
+		err := reader.Read(values, &target)
+
+		assert.EqualError(t, err, "errors occurred while reading the parameters filter.name, limit")
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		for _, name := range []string{"limit", "filter.name"} {
+			missing, ok := multi.ErrorMap()[name].(*qparam.MissingParameterError)
+			require.True(t, ok, "not a MissingParameterError")
+			assert.Equal(t, name, missing.Param)
+		}
+
+		values = url.Values{"limit": []string{"0"}, "filter.name": []string{"Doe"}}
+		err = reader.Read(values, &target)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid required fields", func(t *testing.T) {
+		target := struct {
+			Limit int `param:"limit,required" default:"25"`
+		}{}
+
+		reader := qparam.NewReader()
+		err := reader.Read(url.Values{}, &target)
+
+		assert.Error(t, err)
+		_, ok := err.(qparam.MultiError)
+		assert.False(t, ok, "configuration errors must not be a MultiError")
+	})
+
+	t.Run("collection formats", func(t *testing.T) {
+		type collections struct {
+			Multi []int
+			CSV   []string    `param:"csv,csv"`
+			SSV   []*int      `param:",ssv"`
+			TSV   []time.Time `param:"tsv,tsv"`
+			Pipes []string    `param:"pipes,pipes" default:"a|b"`
+			Other []string    `param:"other,pipes" default:"c|d"`
+		}
+
+		one, two := 1, 2
+		expected := collections{
+			Multi: []int{1, 2},
+			CSV:   []string{"a,b", "c", "d"},
+			SSV:   []*int{&one, &two},
+			TSV:   []time.Time{yesterday, tomorrow},
+			Pipes: []string{"a", "b"},
+			Other: []string{"e"},
+		}
+		values := url.Values{
+			"multi": []string{"1", "2"},
+			"csv":   []string{`"a,b",c`, "d"},
+			"ssv":   []string{"1 2"},
+			"tsv":   []string{yesterdayStr + "\t" + tomorrowStr},
+			"other": []string{"e"},
+		}
+
+		target := collections{}
+		reader := qparam.NewReader()
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, target)
+	})
+
+	t.Run("collection format option", func(t *testing.T) {
+		type item struct {
+			SKU  string
+			Tags []string
+		}
+
+		target := struct {
+			IDs   []int
+			Names []string `param:"names,multi"`
+			Items []item
+		}{}
+		values := url.Values{"ids": []string{"1,2", "3"}, "names": []string{"a,b"}, "items.0.tags": []string{"c,d"}}
+
+		reader := qparam.NewReader(qparam.Collection(qparam.CSV))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, target.IDs)
+		assert.Equal(t, []string{"a,b"}, target.Names)
+		assert.Equal(t, []item{{Tags: []string{"c", "d"}}}, target.Items)
+
+		err = reader.Read(url.Values{"ids": []string{`1,"2`}}, &target)
+		assert.EqualError(t, err, "an error occurred while reading the parameter ids")
+	})
+
+	t.Run("invalid collection formats", func(t *testing.T) {
+		single := struct {
+			ID int `param:"id,csv"`
+		}{}
+		multiple := struct {
+			IDs []int `param:"ids,csv,pipes"`
+		}{}
+		unknown := struct {
+			IDs []int
+		}{}
+		structs := struct {
+			Items []struct{ SKU string } `param:"items,csv"`
+		}{}
+
+		err := qparam.NewReader().Read(url.Values{}, &single)
+		assert.Error(t, err)
+
+		err = qparam.NewReader().Read(url.Values{}, &multiple)
+		assert.Error(t, err)
+
+		err = qparam.NewReader(qparam.Collection("unknown")).Read(url.Values{}, &unknown)
+		assert.Error(t, err)
+
+		err = qparam.NewReader().Read(url.Values{}, &structs)
+		assert.Error(t, err)
+	})
+
+	t.Run("map fields", func(t *testing.T) {
+		type search struct {
+			Query   string
+			Filter  map[string]string
+			Labels  map[string][]int `param:"labels,csv"`
+			Weights map[int]*float64
+		}
+
+		half := 0.5
+		expected := search{
+			Query:   "foo",
+			Filter:  map[string]string{"status": "open", "owner": "bob", "a.b": "c", "existing": "value"},
+			Labels:  map[string][]int{"env": {1, 2}, "team": {3}},
+			Weights: map[int]*float64{3: &half},
+		}
+		values := url.Values{
+			"query":         []string{"foo"},
+			"filter.status": []string{"open"},
+			"filter[owner]": []string{"bob"},
+			"filter.a.b":    []string{"c"},
+			"labels[env]":   []string{"1,2"},
+			"labels.team":   []string{"3"},
+			"weights.3":     []string{"0.5"},
+		}
+
+		target := search{Filter: map[string]string{"existing": "value"}}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, target)
+	})
+
+	t.Run("map errors", func(t *testing.T) {
+		type search struct {
+			Filter  map[string]string
+			Weights map[int]float64
+			Structs map[string]struct{ Name string }
+		}
+
+		values := url.Values{
+			"filter.status": []string{"open", "closed"},
+			"weights.one":   []string{"1"},
+			"weights.2":     []string{"two"},
+			"structs.a":     []string{"b"},
+		}
+
+		target := search{}
+		reader := qparam.NewReader()
+		err := reader.Read(values, &target)
+
+		assert.EqualError(t, err, "errors occurred while reading the parameters "+
+			"filter.status, structs.a, weights.2, weights.one")
+	})
+
+	t.Run("bracket syntax", func(t *testing.T) {
+		type phone struct {
+			Label  string
+			Number string
+		}
+
+		type item struct {
+			Name  string
+			Phone *phone
+		}
+
+		type contact struct {
+			Name   string
+			Phone  phone
+			IDs    []int
+			Tags   []string
+			Items  []item
+			Labels map[string]string
+		}
+
+		expected := contact{
+			Name:   "Doe",
+			Phone:  phone{Label: "Mobile", Number: "+33 112 33445566"},
+			IDs:    []int{1, 2},
+			Tags:   []string{"a", "b", "c"},
+			Items:  []item{{Name: "first", Phone: &phone{Label: "Home"}}, {Name: "second"}},
+			Labels: map[string]string{"env": "prod"},
+		}
+		values := url.Values{
+			"name":                  []string{"Doe"},
+			"phone[label]":          []string{"Mobile"},
+			"phone.number":          []string{"+33 112 33445566"},
+			"ids[]":                 []string{"1", "2"},
+			"tags[1]":               []string{"b"},
+			"tags[0]":               []string{"a"},
+			"tags[2]":               []string{"c"},
+			"items[0][name]":        []string{"first"},
+			"items[0][phone].label": []string{"Home"},
+			"items[1].name":         []string{"second"},
+			"labels[env]":           []string{"prod"},
+		}
+
+		target := contact{}
+		reader := qparam.NewReader(qparam.Brackets(true), qparam.Strict(true))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, target)
+	})
+
+	t.Run("custom separator", func(t *testing.T) {
+		type item struct {
+			Name string
+		}
+
+		type contact struct {
+			Phone  struct{ Label string }
+			Items  []*item
+			Labels map[string]string
+		}
+
+		values := url.Values{
+			"phone__label":   []string{"Mobile"},
+			"items__0__name": []string{"first"},
+			"labels__env":    []string{"prod"},
+		}
+
+		target := contact{}
+		reader := qparam.NewReader(qparam.Separator("__"), qparam.Strict(true))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Mobile", target.Phone.Label)
+		assert.Equal(t, []*item{{Name: "first"}}, target.Items)
+		assert.Equal(t, map[string]string{"env": "prod"}, target.Labels)
+	})
+
+	t.Run("struct slices", func(t *testing.T) {
+		type lineItem struct {
+			SKU string
+			Qty int
+		}
+
+		type order struct {
+			Items []lineItem
+		}
+
+		values := url.Values{
+			"items.0.sku": []string{"A"},
+			"items.0.qty": []string{"2"},
+			"items.1.sku": []string{"B"},
+		}
+
+		target := order{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []lineItem{{SKU: "A", Qty: 2}, {SKU: "B"}}, target.Items)
+
+		t.Run("sparse indexes", func(t *testing.T) {
+			values := url.Values{
+				"items.7.sku": []string{"C"},
+				"items.3.sku": []string{"B"},
+				"items.0.sku": []string{"A"},
+			}
+
+			target := order{}
+			err := reader.Read(values, &target)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []lineItem{{SKU: "A"}, {SKU: "B"}, {SKU: "C"}}, target.Items)
+		})
+
+		t.Run("element errors", func(t *testing.T) {
+			values := url.Values{
+				"items.0.qty":   []string{"1"},
+				"items.1.qty":   []string{"two"},
+				"items.1.color": []string{"red"},
+			}
+
+			target := order{}
+			err := reader.Read(values, &target)
+
+			require.Error(t, err)
+			multi, ok := err.(qparam.MultiError)
+			require.True(t, ok, "not a MultiError")
+			errs := multi.ErrorMap()
+			assert.Equal(t, 2, len(errs))
+			assert.Contains(t, errs, "items.1.qty")
+			assert.Contains(t, errs, "items.1.color")
+		})
+
+		t.Run("max index", func(t *testing.T) {
+			values := url.Values{
+				"items.0.sku":        []string{"A"},
+				"items.10.sku":       []string{"B"},
+				"items.99999999.sku": []string{"C"},
+				"items.99999999.qty": []string{"1"},
+			}
+
+			target := order{}
+			reader := qparam.NewReader(qparam.MaxIndex(10))
+			err := reader.Read(values, &target)
+
+			require.Error(t, err)
+			multi, ok := err.(qparam.MultiError)
+			require.True(t, ok, "not a MultiError")
+			errs := multi.ErrorMap()
+			assert.Equal(t, 2, len(errs))
+			assert.Contains(t, errs, "items.99999999.sku")
+			assert.Contains(t, errs, "items.99999999.qty")
+			assert.Equal(t, []lineItem{{SKU: "A"}, {SKU: "B"}}, target.Items)
+
+			target = order{}
+			reader = qparam.NewReader(qparam.MaxIndex(-1))
+			err = reader.Read(values, &target)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 3, len(target.Items))
+		})
+	})
+
+	t.Run("embedded structs", func(t *testing.T) {
+		type Pagination struct {
+			Limit  int
+			Offset int
+		}
+
+		type Sorting struct {
+			Sort string `param:",required"`
+		}
+
+		type listUsers struct {
+			*Pagination
+			Sorting
+			Page  Pagination `param:"page"`
+			Query string
+		}
+
+		values := url.Values{
+			"limit":      []string{"10"},
+			"offset":     []string{"20"},
+			"sort":       []string{"name"},
+			"page.limit": []string{"5"},
+			"query":      []string{"doe"},
+		}
+
+		target := listUsers{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		require.NotNil(t, target.Pagination)
+		assert.Equal(t, Pagination{Limit: 10, Offset: 20}, *target.Pagination)
+		assert.Equal(t, "name", target.Sort)
+		assert.Equal(t, Pagination{Limit: 5}, target.Page)
+		assert.Equal(t, "doe", target.Query)
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		type item struct {
+			Qty int `min:"1"`
+		}
+
+		type search struct {
+			Limit  int            `min:"1" max:"100" default:"10"`
+			Sort   string         `oneof:"asc|desc"`
+			Query  string         `minlen:"3" maxlen:"20"`
+			Code   *string        `pattern:"^[A-Z]{2}$"`
+			Tags   []string       `param:"tags,csv" maxitems:"3" maxlen:"5"`
+			Items  []item         `maxitems:"2"`
+			Ranges map[string]int `max:"9"`
+		}
+
+		valid := url.Values{
+			"sort":        []string{"asc"},
+			"query":       []string{"doe"},
+			"code":        []string{"DE"},
+			"tags":        []string{"a,b,c"},
+			"items.0.qty": []string{"1"},
+			"ranges.a":    []string{"9"},
+		}
+
+		target := search{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(valid, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, target.Limit)
+
+		invalid := url.Values{
+			"limit":       []string{"0"},
+			"sort":        []string{"up"},
+			"query":       []string{"do"},
+			"code":        []string{"DEU"},
+			"tags":        []string{"a,b", "c,d"},
+			"items.0.qty": []string{"1"},
+			"items.1.qty": []string{"0"},
+			"items.2.qty": []string{"1"},
+			"ranges.a":    []string{"10"},
+		}
+
+		err = reader.Read(invalid, &search{})
+
+		require.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		errs := multi.ErrorMap()
+
+		expected := map[string]string{
+			"limit":    "min",
+			"sort":     "oneof",
+			"query":    "minlen",
+			"code":     "pattern",
+			"tags":     "maxitems",
+			"items":    "maxitems",
+			"ranges.a": "max",
+		}
+		assert.Equal(t, len(expected), len(errs))
+		for name, constraint := range expected {
+			var constraintErr *qparam.ConstraintError
+			require.True(t, errors.As(errs[name], &constraintErr), "no constraint error for %s", name)
+			assert.Equal(t, name, constraintErr.Param)
+			assert.Equal(t, constraint, constraintErr.Constraint)
+		}
+
+		err = reader.Read(url.Values{"items.0.qty": []string{"0"}}, &search{})
+		require.Error(t, err)
+		assert.Contains(t, err.(qparam.MultiError).ErrorMap(), "items.0.qty")
+	})
+
+	t.Run("times and durations", func(t *testing.T) {
+		type report struct {
+			From    time.Time  `layout:"2006-01-02"`
+			To      *time.Time `layout:"2006-01-02"`
+			Since   time.Time  `layout:"unix"`
+			Updated time.Time
+			Timeout time.Duration `default:"30s"`
+		}
+
+		values := url.Values{
+			"from":    []string{"2020-02-01"},
+			"to":      []string{"2020-02-29"},
+			"since":   []string{"1580515200"},
+			"updated": []string{"01.02.2020 12:30"},
+		}
+
+		target := report{}
+		reader := qparam.NewReader(qparam.TimeLayouts(time.RFC3339, "02.01.2006 15:04"), qparam.Location(time.UTC))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), target.From)
+		require.NotNil(t, target.To)
+		assert.Equal(t, time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), *target.To)
+		assert.Equal(t, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), target.Since)
+		assert.Equal(t, time.Date(2020, 2, 1, 12, 30, 0, 0, time.UTC), target.Updated)
+		assert.Equal(t, 30*time.Second, target.Timeout)
+
+		err = reader.Read(url.Values{"from": []string{"01.02.2020"}, "timeout": []string{"5"}}, &report{})
+
+		require.Error(t, err)
+		errs := err.(qparam.MultiError).ErrorMap()
+		assert.Equal(t, 2, len(errs))
+		assert.IsType(t, &qparam.ParseError{}, errs["from"])
+		assert.IsType(t, &qparam.ParseError{}, errs["timeout"])
+
+		writer := qparam.NewWriter(qparam.TimeLayouts(time.RFC3339, "02.01.2006 15:04"), qparam.Location(time.UTC))
+		written, err := writer.Write(&target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, url.Values{
+			"from":    []string{"2020-02-01"},
+			"to":      []string{"2020-02-29"},
+			"since":   []string{"1580515200"},
+			"updated": []string{"2020-02-01T12:30:00Z"},
+			"timeout": []string{"30s"},
+		}, written)
+	})
+
+	t.Run("bool modes", func(t *testing.T) {
+		type flags struct {
+			Verbose bool
+			Debug   *bool
+			Color   bool `default:"true"`
+			Levels  []bool
+		}
+
+		values := url.Values{"verbose": []string{""}, "levels": []string{"yes", "off"}}
+
+		reader := qparam.NewReader()
+		err := reader.Read(values, &flags{})
+
+		require.Error(t, err)
+		errs := err.(qparam.MultiError).ErrorMap()
+		assert.IsType(t, &qparam.ParseError{}, errs["verbose"])
+		assert.IsType(t, &qparam.ParseError{}, errs["levels"])
+
+		target := flags{}
+		reader = qparam.NewReader(qparam.Bools(qparam.LenientBool))
+		err = reader.Read(url.Values{"verbose": []string{"Yes"}, "levels": []string{"on", "n"}}, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, flags{Verbose: true, Color: true, Levels: []bool{true, false}}, target)
+
+		err = reader.Read(values, &flags{})
+		require.Error(t, err)
+		assert.IsType(t, &qparam.ParseError{}, err.(qparam.MultiError).ErrorMap()["verbose"])
+
+		target = flags{}
+		reader = qparam.NewReader(qparam.Bools(qparam.PresenceBool), qparam.Strict(true))
+		err = reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, flags{Verbose: true, Color: false, Levels: []bool{true, false}}, target)
+
+		type opts struct {
+			Flag *bool
+			Dry  bool
+		}
+		type nested struct {
+			Opts *opts
+		}
+
+		nestedTarget := nested{}
+		err = reader.Read(url.Values{}, &nestedTarget)
+
+		assert.NoError(t, err)
+		assert.Nil(t, nestedTarget.Opts)
+
+		err = reader.Read(url.Values{"opts.flag": []string{""}}, &nestedTarget)
+
+		assert.NoError(t, err)
+		require.NotNil(t, nestedTarget.Opts)
+		require.NotNil(t, nestedTarget.Opts.Flag)
+		assert.True(t, *nestedTarget.Opts.Flag)
+		assert.False(t, nestedTarget.Opts.Dry)
+	})
+
+	t.Run("multiple values", func(t *testing.T) {
+		type params struct {
+			Sort   string
+			Limit  int
+			Tags   string            `multiple:"join:,"`
+			Page   int               `multiple:"error"`
+			Filter map[string]string `multiple:"last"`
+		}
+
+		values := url.Values{
+			"sort":          []string{"name", "age"},
+			"limit":         []string{"10", "20"},
+			"tags":          []string{"a", "b"},
+			"filter.status": []string{"open", "closed"},
+		}
+
+		target := params{}
+		reader := qparam.NewReader()
+		err := reader.Read(values, &target)
+
+		require.Error(t, err)
+		errs := err.(qparam.MultiError).ErrorMap()
+		assert.Equal(t, 2, len(errs))
+		assert.Equal(t, &qparam.MultipleValuesError{Param: "sort", Values: []string{"name", "age"}}, errs["sort"])
+		assert.IsType(t, &qparam.MultipleValuesError{}, errs["limit"])
+		assert.Equal(t, "a,b", target.Tags)
+		assert.Equal(t, map[string]string{"status": "closed"}, target.Filter)
+
+		target = params{}
+		reader = qparam.NewReader(qparam.MultipleValues(qparam.FirstValue))
+		err = reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, params{Sort: "name", Limit: 10, Tags: "a,b", Filter: map[string]string{"status": "closed"}}, target)
+
+		err = reader.Read(url.Values{"page": []string{"1", "2"}}, &params{})
+		require.Error(t, err)
+		assert.IsType(t, &qparam.MultipleValuesError{}, err.(qparam.MultiError).ErrorMap()["page"])
+
+		target = params{}
+		reader = qparam.NewReader(qparam.MultipleValues(qparam.JoinValues(" ")))
+		err = reader.Read(url.Values{"sort": []string{"name", "age"}, "limit": []string{"10", "20"}}, &target)
+
+		require.Error(t, err)
+		errs = err.(qparam.MultiError).ErrorMap()
+		assert.Equal(t, 1, len(errs))
+		assert.IsType(t, &qparam.MultipleValuesError{}, errs["limit"])
+		assert.Equal(t, "name age", target.Sort)
+	})
+
+	t.Run("case-insensitive names", func(t *testing.T) {
+		type item struct {
+			Name string
+		}
+
+		type params struct {
+			Limit  int
+			IDs    []int
+			Filter map[string]string
+			Items  []item
+		}
+
+		values := url.Values{
+			"Limit":         []string{"10"},
+			"ids":           []string{"1"},
+			"IDS":           []string{"2"},
+			"FILTER.Status": []string{"open"},
+			"Items.0.NAME":  []string{"a"},
+		}
+
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(values, &params{})
+
+		require.Error(t, err)
+		assert.Equal(t, 4, len(err.(qparam.MultiError).ErrorMap()))
+
+		target := params{}
+		reader = qparam.NewReader(qparam.CaseInsensitive(true), qparam.Strict(true))
+		err = reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, params{
+			Limit:  10,
+			IDs:    []int{2, 1},
+			Filter: map[string]string{"Status": "open"},
+			Items:  []item{{Name: "a"}},
+		}, target)
+	})
+
+	t.Run("aliases", func(t *testing.T) {
+		type params struct {
+			Limit  int               `param:"limit,alias=per_page|pageSize"`
+			Filter map[string]string `param:"filter,alias=f"`
+			Tags   []string          `param:"tags,alias=tag"`
+		}
+
+		values := url.Values{
+			"per_page": []string{"10"},
+			"pageSize": []string{"x"},
+			"f.status": []string{"open"},
+			"tag":      []string{"a"},
+			"tags":     []string{"b"},
+		}
+
+		target := params{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, params{Limit: 10, Filter: map[string]string{"status": "open"}, Tags: []string{"b"}}, target)
+
+		err = reader.Read(url.Values{"per_page": []string{"ten"}}, &params{})
+		require.Error(t, err)
+		assert.IsType(t, &qparam.ParseError{}, err.(qparam.MultiError).ErrorMap()["per_page"])
+
+		target = params{}
+		reader = qparam.NewReader(qparam.AliasConflicts(true), qparam.CaseInsensitive(true))
+		err = reader.Read(values, &target)
+
+		require.Error(t, err)
+		errs := err.(qparam.MultiError).ErrorMap()
+		assert.Equal(t, 2, len(errs))
+		assert.Equal(t, &qparam.AliasConflictError{Param: "limit", Names: []string{"per_page", "pageSize"}}, errs["limit"])
+		assert.Equal(t, &qparam.AliasConflictError{Param: "tags", Names: []string{"tags", "tag"}}, errs["tags"])
+		assert.Equal(t, map[string]string{"status": "open"}, target.Filter)
+
+		target = params{}
+		err = reader.Read(url.Values{"PAGESIZE": []string{"5"}}, &target)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, target.Limit)
+		reader = qparam.NewReader(qparam.AliasConflicts(true), qparam.Strict(true))
+		err = reader.Read(url.Values{"limit": []string{"3"}, "per_page": []string{"4"}}, &params{})
+
+		require.Error(t, err)
+		errs = err.(qparam.MultiError).ErrorMap()
+		assert.Equal(t, 1, len(errs))
+		var conflictErr *qparam.AliasConflictError
+		require.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, []string{"limit", "per_page"}, conflictErr.Names)
+	})
+
+	t.Run("nil nested structs", func(t *testing.T) {
+		expected := test{Pointers: &pointers{Int32Ptr: new(int32)}}
+		*expected.Pointers.Int32Ptr = -253
+		values := url.Values{"pointers.int32ptr": []string{"-253"}}
+
+		target := test{}
+		reader := qparam.NewReader(qparam.Mapper(strcase.SnakeCase))
+		err := reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, target)
+	})
+
+	t.Run("concurrent reads", func(t *testing.T) {
+		values := url.Values{"string": []string{"foo"}, "times.time": []string{nowStr}}
+		reader := qparam.NewReader(qparam.Mapper(strcase.SnakeCase))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				target := test{}
+				stringsTarget := strings{}
+				assert.NoError(t, reader.Read(values, &target, &stringsTarget))
+				assert.Equal(t, now, target.Times.Time)
+				assert.Equal(t, "foo", stringsTarget.String)
+			}()
+		}
+		wg.Wait()
+	})
+
 	t.Run("nested structs", func(t *testing.T) {
 		expected := test{
 			Int:    -345,
@@ -249,3 +1106,56 @@ func TestReader_Read(t *testing.T) {
 		assert.EqualValues(t, expected, target)
 	})
 }
+
+func BenchmarkReader_Read(b *testing.B) {
+	type page struct {
+		Limit  int
+		Offset int
+	}
+
+	type sorting struct {
+		Sort  string
+		Order string
+	}
+
+	type filters struct {
+		Name    string
+		Age     *int
+		Tags    []string
+		Created time.Time
+		Sorting sorting
+	}
+
+	values := url.Values{
+		"limit":         []string{"25"},
+		"offset":        []string{"100"},
+		"name":          []string{"Doe"},
+		"age":           []string{"31"},
+		"tags":          []string{"a", "b", "c"},
+		"created":       []string{nowStr},
+		"sorting.sort":  []string{"name"},
+		"sorting.order": []string{"asc"},
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		reader := qparam.NewReader()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var p page
+			var f filters
+			_ = reader.Read(values, &p, &f)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var p page
+			var f filters
+			_ = qparam.NewReader().Read(values, &p, &f)
+		}
+	})
+}
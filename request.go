@@ -0,0 +1,145 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMaxBodySize = 10 << 20
+	maxMemory          = 32 << 20
+	headerSource       = "header"
+	cookieSource       = "cookie"
+	pathSource         = "path"
+)
+
+// RequestSource defines which parameters of an http request are read by ReadRequest.
+type RequestSource int
+
+// Request sources supported by ReadRequest.
+const (
+	// Form reads the URL query and url encoded form values of the request body. Values of the body
+	// take precedence over query parameters with the same name.
+	Form RequestSource = iota
+	// Query reads the URL query only, the request body is ignored.
+	Query
+	// PostForm reads url encoded form values of the request body only.
+	PostForm
+	// Multipart reads the URL query and the values of a multipart form (or an url encoded form) in
+	// the request body. Values of the body take precedence over query parameters with the same name.
+	// Uploaded files are read into fields of the type *multipart.FileHeader or []*multipart.FileHeader.
+	Multipart
+)
+
+// Request is a functional option which defines the parameters of an http request which are read
+// by ReadRequest (default: Form).
+func Request(source RequestSource) Option {
+	return func(r *Reader) {
+		r.source = source
+	}
+}
+
+// MaxBodySize is a functional option which limits the number of bytes read from the body of an http
+// request by ReadRequest (default: 10MB). Requests with larger bodies are rejected with an error.
+func MaxBodySize(size int64) Option {
+	return func(r *Reader) {
+		r.maxBodySize = size
+	}
+}
+
+// ReadRequest parses the parameters of the http request and assigns them to the matching fields
+// of the target structs. The parameters which are read depend on the configured request source.
+//
+// Besides parameters, headers, cookies and path values of the request can be read into fields with
+// the tags "header", "cookie" and "path", e.g. `header:"X-Request-ID"`, `cookie:"session"` or
+// `path:"id"`. Header names are canonicalized, cookie names and path values must match exactly
+// (see PathValues for path values). Errors for those fields are reported with the
+// name of the source as prefix, e.g. "header:X-Request-Id".
+//
+// If the request source is Multipart, uploaded files are assigned to fields of the type
+// *multipart.FileHeader or []*multipart.FileHeader, which are named like other parameters. The size of
+// each file can be limited by the tag "maxsize" (in bytes), the accepted content types by the tag "accept"
+// (separated by "|"), e.g. `maxsize:"1048576" accept:"image/png|image/*"`. Content types are sent by the
+// client and should therefore not be trusted. Violated limits are reported by a ConstraintError.
+//
+// Errors which occur while reading the parameters are reported like the errors of Read, failures
+// while parsing the request body are reported by errors which don't implement MultiError.
+func (r *Reader) ReadRequest(req *http.Request, targets ...interface{}) error {
+	_, err := r.readRequest(req, targets, false)
+	return err
+}
+
+// readRequest reads the parameters, other sources and uploaded files of the request into the targets.
+func (r *Reader) readRequest(req *http.Request, targets []interface{}, withResult bool) (*Result, error) {
+	params, err := r.requestParams(req)
+	if err != nil {
+		return &Result{}, errors.Wrap(err, "unable to parse request")
+	}
+
+	cookies := url.Values{}
+	for _, cookie := range req.Cookies() {
+		cookies.Add(cookie.Name, cookie.Value)
+	}
+
+	sources := map[string]Source{
+		headerSource: valuesSource(req.Header),
+		cookieSource: valuesSource(cookies),
+	}
+	if r.pathValues != nil {
+		sources[pathSource] = r.pathValues(req)
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if r.source == Multipart && req.MultipartForm != nil {
+		files = req.MultipartForm.File
+	}
+	return r.read(r.newState(params, sources, files), targets, withResult)
+}
+
+// requestParams parses the request and returns the parameters of the configured source.
+func (r *Reader) requestParams(req *http.Request) (url.Values, error) {
+	if r.source == Query {
+		return req.URL.Query(), nil
+	}
+
+	if req.Body != nil && req.PostForm == nil {
+		req.Body = http.MaxBytesReader(nil, req.Body, r.maxBodySize)
+	}
+
+	var body url.Values
+	switch r.source {
+	case Form, PostForm:
+		err := req.ParseForm()
+		if err != nil {
+			return nil, err
+		}
+		body = req.PostForm
+	case Multipart:
+		err := req.ParseMultipartForm(maxMemory)
+		if err != nil && err != http.ErrNotMultipart {
+			return nil, err
+		}
+		body = req.PostForm
+	default:
+		return nil, errors.Errorf("unknown request source %d", r.source)
+	}
+
+	if r.source == PostForm {
+		return body, nil
+	}
+
+	params := make(url.Values, len(body))
+	for name, values := range req.URL.Query() {
+		params[name] = values
+	}
+	for name, values := range body {
+		params[name] = values
+	}
+	return params, nil
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type search struct {
+	Query string `param:"q"`
+	Limit int
+	Tags  []string
+}
+
+func formRequest(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+// upload is a file of a multipart request
+type upload struct {
+	Name, Filename, ContentType, Content string
+}
+
+func uploadRequest(t *testing.T, target string, form url.Values, uploads ...upload) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, values := range form {
+		for _, value := range values {
+			require.NoError(t, writer.WriteField(name, value))
+		}
+	}
+	for _, u := range uploads {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, u.Name, u.Filename))
+		header.Set("Content-Type", u.ContentType)
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(u.Content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestReader_ReadRequest(t *testing.T) {
+	form := url.Values{"q": []string{"body"}, "tags": []string{"a", "b"}}
+
+	t.Run("form", func(t *testing.T) {
+		req := formRequest("/search?q=query&limit=10", form)
+
+		target := search{}
+		reader := qparam.NewReader()
+		err := reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, search{Query: "body", Limit: 10, Tags: []string{"a", "b"}}, target)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		req := formRequest("/search?q=query&limit=10", form)
+
+		target := search{}
+		reader := qparam.NewReader(qparam.Request(qparam.Query))
+		err := reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, search{Query: "query", Limit: 10}, target)
+	})
+
+	t.Run("post form", func(t *testing.T) {
+		req := formRequest("/search?q=query&limit=10", form)
+
+		target := search{}
+		reader := qparam.NewReader(qparam.Request(qparam.PostForm))
+		err := reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, search{Query: "body", Tags: []string{"a", "b"}}, target)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		req := uploadRequest(t, "/search?q=query&limit=10", form)
+
+		target := search{}
+		reader := qparam.NewReader(qparam.Request(qparam.Multipart))
+		err := reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, search{Query: "body", Limit: 10, Tags: []string{"a", "b"}}, target)
+
+		req = formRequest("/search?limit=10", form)
+
+		target = search{}
+		err = reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, search{Query: "body", Limit: 10, Tags: []string{"a", "b"}}, target)
+	})
+
+	t.Run("get request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/search?q=query&tags=a", nil)
+
+		target := search{}
+		reader := qparam.NewReader()
+		err := reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, search{Query: "query", Tags: []string{"a"}}, target)
+	})
+
+	t.Run("max body size", func(t *testing.T) {
+		large := url.Values{"q": []string{strings.Repeat("x", 1024)}}
+
+		reader := qparam.NewReader(qparam.MaxBodySize(512))
+		err := reader.ReadRequest(formRequest("/search", large), &search{})
+
+		assert.Error(t, err)
+		_, ok := err.(qparam.MultiError)
+		assert.False(t, ok, "must not be a MultiError")
+
+		reader = qparam.NewReader(qparam.MaxBodySize(512), qparam.Request(qparam.Multipart))
+		err = reader.ReadRequest(uploadRequest(t, "/search", large), &search{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("headers and cookies", func(t *testing.T) {
+		type meta struct {
+			RequestID string   `header:"x-request-id,required"`
+			Accept    []string `header:"Accept,csv"`
+			Session   string   `cookie:"session"`
+			Theme     string   `cookie:"theme" default:"light"`
+		}
+
+		type params struct {
+			search
+			Meta meta
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/search?q=query", nil)
+		req.Header.Set("X-Request-ID", "abc")
+		req.Header.Set("Accept", "text/html,application/json")
+		req.AddCookie(&http.Cookie{Name: "session", Value: "s3cr3t"})
+		req.AddCookie(&http.Cookie{Name: "Theme", Value: "dark"})
+
+		target := params{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "query", target.Query)
+		expected := meta{RequestID: "abc", Accept: []string{"text/html", "application/json"}, Session: "s3cr3t",
+			Theme: "light"}
+		assert.Equal(t, expected, target.Meta)
+	})
+
+	t.Run("sources are ignored by read", func(t *testing.T) {
+		type params struct {
+			Query     string `param:"q"`
+			RequestID string `header:"X-Request-ID,required"`
+			Theme     string `cookie:"theme" default:"light"`
+		}
+
+		target := params{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.Read(url.Values{"q": []string{"query"}}, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, params{Query: "query"}, target)
+	})
+
+	t.Run("header errors", func(t *testing.T) {
+		type params struct {
+			RequestID string `header:"X-Request-ID,required"`
+			Retries   int    `header:"X-Retries"`
+			Limit     int    `cookie:"limit"`
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/search?X-Retries=1", nil)
+		req.Header.Set("X-Retries", "many")
+		req.AddCookie(&http.Cookie{Name: "limit", Value: "none"})
+
+		reader := qparam.NewReader()
+		err := reader.ReadRequest(req, &params{})
+
+		require.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		errs := multi.ErrorMap()
+		assert.Equal(t, 3, len(errs))
+		assert.IsType(t, &qparam.MissingParameterError{}, errs["header:X-Request-Id"], "header names must be canonical")
+		assert.IsType(t, &qparam.ParseError{}, errs["header:X-Retries"])
+		assert.IsType(t, &qparam.ParseError{}, errs["cookie:limit"])
+	})
+
+	t.Run("checkboxes", func(t *testing.T) {
+		type settings struct {
+			Name       string
+			Newsletter bool `default:"true"`
+			Terms      bool
+		}
+
+		req := formRequest("/settings", url.Values{"name": []string{"doe"}, "terms": []string{"on"}})
+
+		target := settings{}
+		reader := qparam.NewReader(qparam.Request(qparam.PostForm), qparam.Bools(qparam.PresenceBool))
+		err := reader.ReadRequest(req, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, settings{Name: "doe", Newsletter: false, Terms: true}, target)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		type profile struct {
+			Name        string
+			Avatar      *multipart.FileHeader   `maxsize:"16" accept:"image/*"`
+			Attachments []*multipart.FileHeader `maxitems:"2"`
+			Resume      *multipart.FileHeader
+		}
+
+		req := uploadRequest(t, "/profile", url.Values{"name": []string{"doe"}},
+			upload{Name: "avatar", Filename: "me.png", ContentType: "image/png", Content: "png"},
+			upload{Name: "attachments", Filename: "a.txt", ContentType: "text/plain", Content: "a"},
+			upload{Name: "attachments", Filename: "b.txt", ContentType: "text/plain", Content: "b"})
+
+		target := profile{}
+		reader := qparam.NewReader(qparam.Request(qparam.Multipart), qparam.Strict(true))
+		err := reader.ReadRequest(req, &target)
+
+		require.NoError(t, err)
+		assert.Equal(t, "doe", target.Name)
+		require.NotNil(t, target.Avatar)
+		assert.Equal(t, "me.png", target.Avatar.Filename)
+		assert.Equal(t, int64(3), target.Avatar.Size)
+		require.Equal(t, 2, len(target.Attachments))
+		assert.Equal(t, "a.txt", target.Attachments[0].Filename)
+		assert.Equal(t, "b.txt", target.Attachments[1].Filename)
+		assert.Nil(t, target.Resume)
+
+		file, err := target.Avatar.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+		assert.Equal(t, "png", string(content))
+
+		req = uploadRequest(t, "/profile", nil,
+			upload{Name: "avatar", Filename: "me.pdf", ContentType: "application/pdf", Content: "pdf"},
+			upload{Name: "attachments", Filename: "a.txt", ContentType: "text/plain", Content: "a"},
+			upload{Name: "attachments", Filename: "b.txt", ContentType: "text/plain", Content: "b"},
+			upload{Name: "attachments", Filename: "c.txt", ContentType: "text/plain", Content: "c"},
+			upload{Name: "resume", Filename: "a.txt", ContentType: "text/plain", Content: "a"},
+			upload{Name: "resume", Filename: "b.txt", ContentType: "text/plain", Content: "b"},
+			upload{Name: "other", Filename: "other.txt", ContentType: "text/plain", Content: "other"})
+
+		err = reader.ReadRequest(req, &profile{})
+
+		require.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		errs := multi.ErrorMap()
+		assert.Equal(t, 4, len(errs))
+		assert.Equal(t, &qparam.ConstraintError{Param: "avatar", Value: "me.pdf", Constraint: "accept",
+			Limit: "image/*"}, errs["avatar"])
+		assert.Equal(t, &qparam.ConstraintError{Param: "attachments", Value: "3", Constraint: "maxitems",
+			Limit: "2"}, errs["attachments"])
+		assert.Equal(t, &qparam.MultipleValuesError{Param: "resume", Values: []string{"a.txt", "b.txt"}}, errs["resume"])
+		assert.IsType(t, &qparam.UnknownParameterError{}, errs["other"])
+
+		req = uploadRequest(t, "/profile", nil,
+			upload{Name: "avatar", Filename: "me.png", ContentType: "image/png", Content: "a very large image"})
+
+		err = reader.ReadRequest(req, &profile{})
+
+		require.Error(t, err)
+		assert.Equal(t, &qparam.ConstraintError{Param: "avatar", Value: "me.png", Constraint: "maxsize", Limit: "16"},
+			err.(qparam.MultiError).ErrorMap()["avatar"])
+	})
+
+	t.Run("parameter errors", func(t *testing.T) {
+		req := formRequest("/search?limit=ten", form)
+
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.ReadRequest(req, &struct{ Limit int }{})
+
+		require.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		assert.Equal(t, 3, len(multi.ErrorMap()))
+	})
+}
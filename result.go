@@ -0,0 +1,74 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// Result contains information about a call to ReadWithResult or ReadRequestWithResult which is not an
+// error, e.g. warnings about deprecated parameters or the fields which were assigned. Names of parameters
+// and uploaded files are sorted.
+type Result struct {
+	// Warnings are reported for parameters which were read, but should be changed by the client.
+	Warnings []Warning
+	// Consumed contains the names of all parameters and uploaded files which belong to a field of the
+	// targets, as they were passed to the reader (e.g. "per_page" or "items.0.name").
+	Consumed []string
+	// Unconsumed contains the names of all parameters and uploaded files which don't belong to any field,
+	// also if the reader is not in strict mode.
+	Unconsumed []string
+	// Assigned contains the names of all fields which were assigned a value from the parameters, other
+	// sources or uploaded files, in the form of the parameter name of the field without aliases (e.g.
+	// "limit", "items.0.name" or "header:X-Request-Id"). Fields with default values or flags which
+	// were set to false because of a missing parameter are not contained, neither are fields whose
+	// parameters couldn't be read.
+	Assigned []string
+}
+
+// IsAssigned returns true if the field with the provided name was assigned a value from the parameters,
+// e.g. in order to distinguish "?limit=0" from a missing parameter. See Assigned for the form of the names.
+func (res *Result) IsAssigned(name string) bool {
+	i := sort.SearchStrings(res.Assigned, name)
+	return i < len(res.Assigned) && res.Assigned[i] == name
+}
+
+// Warning reports a parameter with a deprecated name, see the tag option "deprecated".
+type Warning struct {
+	// Param is the name of the parameter with a deprecated name as it was read, e.g. "per_page" or
+	// "f.status" for a map.
+	Param string
+	// Replacement is the name which should be used instead, empty if all names of the field are
+	// deprecated.
+	Replacement string
+}
+
+// String returns a message which describes the warning, e.g. for the http header Warning.
+func (w Warning) String() string {
+	if w.Replacement == "" {
+		return fmt.Sprintf("parameter %q is deprecated", w.Param)
+	}
+	return fmt.Sprintf("parameter %q is deprecated, use %q instead", w.Param, w.Replacement)
+}
+
+// ReadWithResult works like Read, but returns a result which contains warnings about deprecated
+// parameters and the names of consumed parameters and assigned fields in addition to errors. The
+// returned result is never nil, warnings are also reported if an error is returned.
+func (r *Reader) ReadWithResult(params url.Values, targets ...interface{}) (*Result, error) {
+	return r.read(r.newState(params, nil, nil), targets, true)
+}
+
+// ReadRequestWithResult works like ReadRequest, but returns a result like ReadWithResult. This allows
+// middleware to inform clients about deprecated parameters, e.g. using the http header Warning:
+//
+//	result, err := reader.ReadRequestWithResult(req, &params)
+//	for _, warning := range result.Warnings {
+//		w.Header().Add("Warning", fmt.Sprintf("299 - %q", warning))
+//	}
+func (r *Reader) ReadRequestWithResult(req *http.Request, targets ...interface{}) (*Result, error) {
+	return r.readRequest(req, targets, true)
+}
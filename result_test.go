@@ -0,0 +1,182 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_ReadWithResult(t *testing.T) {
+	type item struct {
+		Name string `param:"name,alias=title,deprecated=title"`
+	}
+
+	type params struct {
+		Limit  int               `param:"limit,alias=per_page|pageSize,deprecated=per_page|pageSize"`
+		Sort   string            `param:"sort,deprecated"`
+		Filter map[string]string `param:"filter,alias=f,deprecated=f"`
+		Items  []item
+		Page   int
+	}
+
+	t.Run("deprecated names", func(t *testing.T) {
+		values := url.Values{
+			"per_page":      []string{"10"},
+			"sort":          []string{"name"},
+			"f.status":      []string{"open"},
+			"items.0.title": []string{"a"},
+			"items.1.name":  []string{"b"},
+			"page":          []string{"2"},
+		}
+
+		target := params{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		result, err := reader.ReadWithResult(values, &target)
+
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, params{
+			Limit:  10,
+			Sort:   "name",
+			Filter: map[string]string{"status": "open"},
+			Items:  []item{{Name: "a"}, {Name: "b"}},
+			Page:   2,
+		}, target)
+		assert.Equal(t, []qparam.Warning{
+			{Param: "per_page", Replacement: "limit"},
+			{Param: "sort"},
+			{Param: "f.status", Replacement: "filter.status"},
+			{Param: "items.0.title", Replacement: "items.0.name"},
+		}, result.Warnings)
+
+		assert.Equal(t, `parameter "per_page" is deprecated, use "limit" instead`, result.Warnings[0].String())
+		assert.Equal(t, `parameter "sort" is deprecated`, result.Warnings[1].String())
+	})
+
+	t.Run("no warnings", func(t *testing.T) {
+		result, err := qparam.NewReader().ReadWithResult(url.Values{"limit": []string{"10"}}, &params{})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("warnings with errors", func(t *testing.T) {
+		values := url.Values{"per_page": []string{"ten"}, "pageSize": []string{"10"}}
+		result, err := qparam.NewReader().ReadWithResult(values, &params{})
+
+		require.Error(t, err)
+		assert.IsType(t, &qparam.ParseError{}, err.(qparam.MultiError).ErrorMap()["per_page"])
+		assert.Equal(t, []qparam.Warning{
+			{Param: "per_page", Replacement: "limit"},
+			{Param: "pageSize", Replacement: "limit"},
+		}, result.Warnings)
+	})
+
+	t.Run("deprecated name first", func(t *testing.T) {
+		type page struct {
+			PerPage int `param:"per_page,alias=limit,deprecated=per_page"`
+		}
+
+		target := page{}
+		reader := qparam.NewReader(qparam.CaseInsensitive(true))
+		result, err := reader.ReadWithResult(url.Values{"PER_PAGE": []string{"3"}}, &target)
+
+		require.NoError(t, err)
+		assert.Equal(t, page{PerPage: 3}, target)
+		assert.Equal(t, []qparam.Warning{{Param: "PER_PAGE", Replacement: "limit"}}, result.Warnings)
+		assert.Equal(t, []string{"PER_PAGE"}, result.Consumed)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		result, err := qparam.NewReader().ReadWithResult(url.Values{}, params{})
+
+		assert.Error(t, err)
+		assert.NotNil(t, result)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/search?pageSize=5", nil)
+
+		target := params{}
+		result, err := qparam.NewReader().ReadRequestWithResult(req, &target)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, target.Limit)
+		assert.Equal(t, []qparam.Warning{{Param: "pageSize", Replacement: "limit"}}, result.Warnings)
+	})
+
+	t.Run("assigned with aliases", func(t *testing.T) {
+		type order struct {
+			Items []item `param:"items,alias=lines"`
+		}
+
+		target := order{}
+		result, err := qparam.NewReader().ReadWithResult(url.Values{"lines.0.title": []string{"a"}}, &target)
+
+		require.NoError(t, err)
+		assert.Equal(t, order{Items: []item{{Name: "a"}}}, target)
+		assert.Equal(t, []string{"items", "items.0.name"}, result.Assigned)
+		assert.True(t, result.IsAssigned("items.0.name"))
+		assert.Equal(t, []string{"lines.0.title"}, result.Consumed)
+	})
+
+	t.Run("assigned and consumed", func(t *testing.T) {
+		type patch struct {
+			Limit     int    `param:"limit,alias=per_page|pageSize,deprecated=per_page|pageSize"`
+			Sort      string `default:"name"`
+			Verbose   bool
+			Filter    map[string]string `param:"filter"`
+			IDs       []int             `param:"ids"`
+			Items     []item
+			Page      int
+			RequestID string `header:"X-Request-ID"`
+		}
+
+		values := url.Values{
+			"per_page":      []string{"0"},
+			"pageSize":      []string{"5"},
+			"filter.status": []string{"open"},
+			"ids.1":         []string{"3"},
+			"items.0.name":  []string{"a"},
+			"page":          []string{"two"},
+			"unknown":       []string{"x"},
+			"other":         []string{},
+		}
+
+		target := patch{}
+		reader := qparam.NewReader(qparam.Bools(qparam.PresenceBool))
+		result, err := reader.ReadWithResult(values, &target)
+
+		require.Error(t, err)
+		assert.Equal(t, 1, len(err.(qparam.MultiError).ErrorMap()))
+		assert.Equal(t, []string{"filter", "ids", "items", "items.0.name", "limit"}, result.Assigned)
+		assert.Equal(t, []string{"filter.status", "ids.1", "items.0.name", "page", "pageSize", "per_page"}, result.Consumed)
+		assert.Equal(t, []string{"other", "unknown"}, result.Unconsumed)
+
+		assert.True(t, result.IsAssigned("limit"))
+		assert.Equal(t, 0, target.Limit)
+		assert.False(t, result.IsAssigned("sort"))
+		assert.Equal(t, "name", target.Sort)
+		assert.False(t, result.IsAssigned("verbose"))
+		assert.False(t, result.IsAssigned("page"))
+		assert.False(t, result.IsAssigned("per_page"))
+
+		req := httptest.NewRequest(http.MethodGet, "/items?page=1", nil)
+		req.Header.Set("X-Request-ID", "abc")
+
+		result, err = reader.ReadRequestWithResult(req, &patch{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"header:X-Request-Id", "page"}, result.Assigned)
+		assert.Equal(t, []string{"page"}, result.Consumed)
+		assert.Empty(t, result.Unconsumed)
+	})
+}
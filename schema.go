@@ -0,0 +1,259 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stoewer/go-qparam/internal"
+)
+
+// docTag is the struct tag which contains the description of a parameter
+const docTag = "doc"
+
+// Parameter describes a parameter like an OpenAPI 3 parameter object.
+type Parameter struct {
+	Name            string           `json:"name" yaml:"name"`
+	In              string           `json:"in" yaml:"in"`
+	Description     string           `json:"description,omitempty" yaml:"description,omitempty"`
+	Required        bool             `json:"required,omitempty" yaml:"required,omitempty"`
+	Deprecated      bool             `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool             `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	Style           string           `json:"style,omitempty" yaml:"style,omitempty"`
+	Explode         *bool            `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Schema          *ParameterSchema `json:"schema" yaml:"schema"`
+}
+
+// ParameterSchema describes the values of a parameter like an OpenAPI 3 schema object.
+type ParameterSchema struct {
+	Type                 string           `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string           `json:"format,omitempty" yaml:"format,omitempty"`
+	Items                *ParameterSchema `json:"items,omitempty" yaml:"items,omitempty"`
+	AdditionalProperties *ParameterSchema `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Default              interface{}      `json:"default,omitempty" yaml:"default,omitempty"`
+	Enum                 []interface{}    `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum              *float64         `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *float64         `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MinLength            *int             `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *int             `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Pattern              string           `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	MaxItems             *int             `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+}
+
+// Schema describes the parameters which are read into the target struct by the reader, in the form of
+// OpenAPI 3 parameter objects. Names and collection formats are determined by the configuration of the
+// reader, descriptions are taken from the tag "doc". Fields with the tags "header", "cookie" and "path"
+// are described as parameters in the respective location. Bool fields read with the mode PresenceBool
+// allow empty values. Parameters are marked as deprecated if the name of the field is deprecated, aliases
+// are not described.
+//
+// Maps are described as objects with the style deepObject (e.g. "filter[status]=open"). Slices of structs,
+// slices with the collection format tsv and fields with unsupported types can't be described and are
+// omitted.
+func Schema(reader *Reader, target interface{}) ([]Parameter, error) {
+	t := reflect.TypeOf(target)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("target must be a struct")
+	}
+
+	plan, err := reader.plan(t)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]Parameter, 0, len(plan.Fields))
+	for i := range plan.Fields {
+		field := &plan.Fields[i]
+		if field.Struct || field.Elem != nil || field.Parser == nil || field.Delimiter == "\t" {
+			continue
+		}
+
+		param := Parameter{
+			Name:            field.Path,
+			In:              "query",
+			Description:     field.Tag.Get(docTag),
+			Required:        field.Required,
+			Deprecated:      field.IsDeprecated(field.Path),
+			AllowEmptyValue: field.Flag && field.Source == "",
+			Schema:          valueSchema(field),
+		}
+
+		switch {
+		case field.Source != "":
+			param.In = field.Source
+			param.Required = field.Required || field.Source == pathSource
+		case field.Map:
+			param.Style, param.Explode = "deepObject", explode(true)
+			param.Schema = &ParameterSchema{Type: "object", AdditionalProperties: param.Schema}
+		case field.Slice:
+			param.Style, param.Explode = collectionStyle(field.Delimiter)
+		}
+
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+// valueSchema creates the schema for the values of a field (or the values of a map)
+func valueSchema(field *internal.Field) *ParameterSchema {
+	elem := field.Type
+	if field.Map {
+		elem = elem.Elem()
+	}
+
+	var schema *ParameterSchema
+	if field.Slice {
+		elem = elem.Elem()
+		schema = &ParameterSchema{Type: "array", Items: &ParameterSchema{}}
+		if field.MaxItems > 0 {
+			schema.MaxItems = intPtr(field.MaxItems)
+		}
+	}
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	items := &ParameterSchema{}
+	if schema != nil {
+		items = schema.Items
+	} else {
+		schema = items
+	}
+
+	items.Type, items.Format = typeFormat(elem)
+	if parser, ok := field.Parser.(internal.TimeParser); ok {
+		switch parser.Layouts[0] {
+		case Unix, UnixMilli:
+			items.Type, items.Format = "integer", "int64"
+		case dateLayout:
+			items.Format = "date"
+		case time.RFC3339, time.RFC3339Nano:
+			items.Format = "date-time"
+		default:
+			items.Format = ""
+		}
+	}
+	for _, constraint := range field.Constraints {
+		switch constraint.Name {
+		case "min", "max":
+			limit, err := strconv.ParseFloat(constraint.Limit, 64)
+			if err != nil {
+				continue
+			}
+			if constraint.Name == "min" {
+				items.Minimum = &limit
+			} else {
+				items.Maximum = &limit
+			}
+		case "minlen", "maxlen":
+			limit, _ := strconv.Atoi(constraint.Limit)
+			if constraint.Name == "minlen" {
+				items.MinLength = intPtr(limit)
+			} else {
+				items.MaxLength = intPtr(limit)
+			}
+		case "oneof":
+			for _, option := range strings.Split(constraint.Limit, "|") {
+				items.Enum = append(items.Enum, parseExample(field.Parser, elem, option))
+			}
+		case "pattern":
+			items.Pattern = constraint.Limit
+		}
+	}
+
+	if field.Flag {
+		schema.Default = false
+	} else if field.Default != nil {
+		defaults, err := internal.Split(field.Default, field.Delimiter)
+		if err == nil && field.Slice {
+			values := make([]interface{}, 0, len(defaults))
+			for _, def := range defaults {
+				values = append(values, parseExample(field.Parser, elem, def))
+			}
+			schema.Default = values
+		} else if err == nil && len(defaults) == 1 {
+			schema.Default = parseExample(field.Parser, elem, defaults[0])
+		}
+	}
+
+	return schema
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// dateLayout is the layout of full-date values as defined by RFC 3339
+const dateLayout = "2006-01-02"
+
+// typeFormat returns the OpenAPI type and format for values of the provided type
+func typeFormat(t reflect.Type) (string, string) {
+	if t == timeType {
+		return "string", "date-time"
+	}
+	if t == durationType {
+		return "string", ""
+	}
+
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "integer", "int32"
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return "integer", "int64"
+	case reflect.Uint, reflect.Uint64:
+		return "integer", ""
+	case reflect.Float32:
+		return "number", "float"
+	case reflect.Float64:
+		return "number", "double"
+	case reflect.Bool:
+		return "boolean", ""
+	}
+	return "string", ""
+}
+
+// collectionStyle returns the OpenAPI style and explode values for the delimiter of a collection format
+func collectionStyle(delim string) (string, *bool) {
+	switch delim {
+	case ",":
+		return "form", explode(false)
+	case " ":
+		return "spaceDelimited", explode(false)
+	case "|":
+		return "pipeDelimited", explode(false)
+	}
+	return "form", explode(true)
+}
+
+// parseExample parses a value from a struct tag, the value is returned as string if parsing fails or
+// if the parsed value is not a number or bool.
+func parseExample(parser internal.Parser, t reflect.Type, s string) interface{} {
+	value := reflect.New(t).Elem()
+	if parser.Parse(value, s) != nil {
+		return s
+	}
+
+	switch typ, _ := typeFormat(t); typ {
+	case "integer", "number", "boolean":
+		return value.Interface()
+	}
+	return s
+}
+
+func explode(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stoewer/go-strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	type pagination struct {
+		Limit  int32 `min:"1" max:"100" default:"25" doc:"Maximum number of results"`
+		Offset uint
+	}
+
+	type listUsers struct {
+		pagination
+		Query     string   `param:"q,required" minlen:"3" pattern:"^[a-z]+$"`
+		Sort      string   `oneof:"asc|desc" default:"asc"`
+		IDs       []int64  `param:"ids,csv" maxitems:"10"`
+		Tags      []string `param:"tags,tsv"`
+		Names     []string `default:"a,b"`
+		Ratio     *float64 `oneof:"0.5|1"`
+		Active    bool
+		Created   time.Time
+		Filter    map[string]string
+		Contact   struct{ Email string }
+		Items     []struct{ SKU string }
+		Complex   complex64
+		RequestID string `header:"X-Request-ID"`
+		Session   string `cookie:"session"`
+		ID        int    `path:"id"`
+	}
+
+	expected := `[
+		{"name": "limit", "in": "query", "description": "Maximum number of results",
+			"schema": {"type": "integer", "format": "int32", "default": 25, "minimum": 1, "maximum": 100}},
+		{"name": "offset", "in": "query", "schema": {"type": "integer"}},
+		{"name": "q", "in": "query", "required": true, "schema": {"type": "string", "minLength": 3, "pattern": "^[a-z]+$"}},
+		{"name": "sort", "in": "query", "schema": {"type": "string", "default": "asc", "enum": ["asc", "desc"]}},
+		{"name": "ids", "in": "query", "style": "form", "explode": false,
+			"schema": {"type": "array", "items": {"type": "integer", "format": "int64"}, "maxItems": 10}},
+		{"name": "names", "in": "query", "style": "form", "explode": true,
+			"schema": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"]}},
+		{"name": "ratio", "in": "query", "schema": {"type": "number", "format": "double", "enum": [0.5, 1]}},
+		{"name": "active", "in": "query", "schema": {"type": "boolean"}},
+		{"name": "created", "in": "query", "schema": {"type": "string", "format": "date-time"}},
+		{"name": "filter", "in": "query", "style": "deepObject", "explode": true,
+			"schema": {"type": "object", "additionalProperties": {"type": "string"}}},
+		{"name": "contact.email", "in": "query", "schema": {"type": "string"}},
+		{"name": "X-Request-Id", "in": "header", "schema": {"type": "string"}},
+		{"name": "session", "in": "cookie", "schema": {"type": "string"}},
+		{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}}
+	]`
+
+	reader := qparam.NewReader(qparam.Mapper(strcase.SnakeCase))
+	params, err := qparam.Schema(reader, &listUsers{})
+	require.NoError(t, err)
+
+	actual, err := json.Marshal(params)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(actual))
+
+	t.Run("no struct", func(t *testing.T) {
+		_, err := qparam.Schema(reader, "no struct")
+		assert.Error(t, err)
+
+		_, err = qparam.Schema(reader, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("bracket syntax", func(t *testing.T) {
+		target := struct {
+			Contact struct{ Email string }
+		}{}
+
+		params, err := qparam.Schema(qparam.NewReader(qparam.Brackets(true)), target)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(params))
+		assert.Equal(t, "contact[email]", params[0].Name)
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		target := struct {
+			Verbose bool `default:"true"`
+			Levels  []bool
+		}{}
+
+		params, err := qparam.Schema(qparam.NewReader(qparam.Bools(qparam.PresenceBool)), target)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(params))
+		assert.True(t, params[0].AllowEmptyValue)
+		assert.Equal(t, false, params[0].Schema.Default)
+		assert.False(t, params[1].AllowEmptyValue)
+	})
+
+	t.Run("deprecated", func(t *testing.T) {
+		target := struct {
+			Limit int    `param:"limit,alias=per_page,deprecated=per_page"`
+			Sort  string `param:"sort,deprecated"`
+		}{}
+
+		params, err := qparam.Schema(qparam.NewReader(), target)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(params))
+		assert.False(t, params[0].Deprecated)
+		assert.True(t, params[1].Deprecated)
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// defaultPathValues provides the path values of requests if no other function is configured, see
+// ServeMuxSource.
+var defaultPathValues func(*http.Request) Source
+
+// Source provides values by name, e.g. query parameters or the path values of an http request.
+type Source interface {
+	// Lookup returns the values with the provided name. The second returned value is false if
+	// the source contains no values with this name.
+	Lookup(name string) ([]string, bool)
+	// Keys returns the names of all values of the source. Sources which are unable to enumerate
+	// their names return nil.
+	Keys() []string
+}
+
+// ValuesSource creates a source from url.Values.
+func ValuesSource(values url.Values) Source {
+	return valuesSource(values)
+}
+
+// MapSource creates a source from a map with a single value per name, e.g. the path values
+// returned by gorilla/mux.Vars.
+func MapSource(values map[string]string) Source {
+	return mapSource(values)
+}
+
+// PathValues is a functional option which defines how ReadRequest obtains the path values of an
+// http request, which are read into fields with the tag "path" (e.g. `path:"id"`). Path values are
+// provided by the router, e.g. for gorilla/mux:
+//
+//	qparam.PathValues(func(req *http.Request) qparam.Source {
+//		return qparam.MapSource(mux.Vars(req))
+//	})
+//
+// If compiled with Go 1.22 or later, the path values of the http.ServeMux are used by default
+// (see ServeMuxSource).
+func PathValues(values func(*http.Request) Source) Option {
+	return func(r *Reader) {
+		r.pathValues = values
+	}
+}
+
+// ReadSource reads the values of the provided source into the matching fields of the target structs
+// in the same way as Read does it with url.Values. The values are also read into fields with the tag
+// "path". Values are looked up by the names of the fields, the names returned by Keys are only needed
+// for maps, indexed slices and unknown parameters in strict mode.
+func (r *Reader) ReadSource(source Source, targets ...interface{}) error {
+	state := r.newState(sourceValues(source), map[string]Source{pathSource: source}, nil)
+	state.lookup = source
+	_, err := r.read(state, targets, false)
+	return err
+}
+
+// sourceValues copies the values of all names returned by Keys into url.Values
+func sourceValues(source Source) url.Values {
+	if values, ok := source.(valuesSource); ok {
+		return url.Values(values)
+	}
+
+	keys := source.Keys()
+	values := make(url.Values, len(keys))
+	for _, key := range keys {
+		if v, ok := source.Lookup(key); ok {
+			values[key] = v
+		}
+	}
+	return values
+}
+
+type valuesSource url.Values
+
+func (s valuesSource) Lookup(name string) ([]string, bool) {
+	values, ok := s[name]
+	return values, ok
+}
+
+func (s valuesSource) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+type mapSource map[string]string
+
+func (s mapSource) Lookup(name string) ([]string, bool) {
+	value, ok := s[name]
+	if !ok {
+		return nil, false
+	}
+	return []string{value}, true
+}
+
+func (s mapSource) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+//go:build go1.22
+
+package qparam
+
+import "net/http"
+
+func init() {
+	defaultPathValues = ServeMuxSource
+}
+
+// ServeMuxSource creates a source for the path values of a request which was routed by an
+// http.ServeMux, e.g. the value "id" of the pattern "/items/{id}". Path values can't be enumerated,
+// therefore the source returns no keys.
+func ServeMuxSource(req *http.Request) Source {
+	return serveMuxSource{req: req}
+}
+
+type serveMuxSource struct {
+	req *http.Request
+}
+
+func (s serveMuxSource) Lookup(name string) ([]string, bool) {
+	value := s.req.PathValue(name)
+	if value == "" {
+		return nil, false
+	}
+	return []string{value}, true
+}
+
+func (s serveMuxSource) Keys() []string {
+	return nil
+}
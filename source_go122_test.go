@@ -0,0 +1,54 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+//go:build go1.22
+
+// enable patterns of http.ServeMux, which are disabled for modules requiring Go versions before 1.22
+//go:debug httpmuxgo121=0
+
+package qparam_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMuxSource(t *testing.T) {
+	type params struct {
+		ID    int    `path:"id"`
+		Path  string `path:"path"`
+		Limit int
+	}
+
+	reader := qparam.NewReader()
+
+	type item struct {
+		ID   int
+		Path string
+	}
+
+	var target params
+	var values item
+	var err, sourceErr error
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}/{path...}", func(w http.ResponseWriter, req *http.Request) {
+		err = reader.ReadRequest(req, &target)
+		sourceErr = reader.ReadSource(qparam.ServeMuxSource(req), &values)
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/42/a/b?limit=10", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, params{ID: 42, Path: "a/b", Limit: 10}, target)
+	assert.NoError(t, sourceErr)
+	assert.Equal(t, item{ID: 42, Path: "a/b"}, values)
+
+	source := qparam.ServeMuxSource(httptest.NewRequest(http.MethodGet, "/", nil))
+	_, ok := source.Lookup("id")
+	assert.False(t, ok)
+	assert.Nil(t, source.Keys())
+}
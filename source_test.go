@@ -0,0 +1,149 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doubleSource is a custom source which provides each value twice
+type doubleSource map[string]string
+
+func (s doubleSource) Lookup(name string) ([]string, bool) {
+	value, ok := s[name]
+	if !ok {
+		return nil, false
+	}
+	return []string{value, value}, true
+}
+
+func (s doubleSource) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// keylessSource is a custom source which is unable to enumerate its names
+type keylessSource map[string]string
+
+func (s keylessSource) Lookup(name string) ([]string, bool) {
+	value, ok := s[name]
+	if !ok {
+		return nil, false
+	}
+	return []string{value}, true
+}
+
+func (s keylessSource) Keys() []string {
+	return nil
+}
+
+func TestReader_ReadSource(t *testing.T) {
+	type item struct {
+		ID    int
+		Name  string
+		Items []string
+	}
+
+	t.Run("map source", func(t *testing.T) {
+		target := item{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.ReadSource(qparam.MapSource(map[string]string{"id": "5", "name": "foo"}), &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, item{ID: 5, Name: "foo"}, target)
+	})
+
+	t.Run("values source", func(t *testing.T) {
+		values := url.Values{"id": []string{"5"}, "items": []string{"a", "b"}}
+
+		target := item{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.ReadSource(qparam.ValuesSource(values), &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, item{ID: 5, Items: []string{"a", "b"}}, target)
+	})
+
+	t.Run("custom source", func(t *testing.T) {
+		target := item{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.ReadSource(doubleSource{"items": "a", "unknown": "x"}, &target)
+
+		require.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		assert.Equal(t, 1, len(multi.ErrorMap()))
+		assert.Contains(t, multi.ErrorMap(), "unknown")
+		assert.Equal(t, item{Items: []string{"a", "a"}}, target)
+	})
+
+	t.Run("source without keys", func(t *testing.T) {
+		target := item{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.ReadSource(keylessSource{"id": "5", "name": "foo"}, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, item{ID: 5, Name: "foo"}, target)
+	})
+
+	t.Run("path values", func(t *testing.T) {
+		type params struct {
+			ID   int `path:"id,required"`
+			Page int
+		}
+
+		target := params{}
+		reader := qparam.NewReader(qparam.Strict(true))
+		err := reader.ReadSource(qparam.MapSource(map[string]string{"id": "7", "page": "2"}), &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, params{ID: 7, Page: 2}, target)
+	})
+}
+
+func TestReader_ReadRequest_PathValues(t *testing.T) {
+	type params struct {
+		ID    int    `path:"id,required"`
+		Slug  string `path:"slug"`
+		Limit int
+	}
+
+	vars := map[string]string{"id": "42", "slug": "foo"}
+	reader := qparam.NewReader(qparam.Strict(true), qparam.PathValues(func(*http.Request) qparam.Source {
+		return qparam.MapSource(vars)
+	}))
+
+	t.Run("valid", func(t *testing.T) {
+		target := params{}
+		err := reader.ReadRequest(httptest.NewRequest(http.MethodGet, "/items/42?limit=10", nil), &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, params{ID: 42, Slug: "foo", Limit: 10}, target)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		vars = map[string]string{"id": "foo"}
+
+		target := params{}
+		err := reader.ReadRequest(httptest.NewRequest(http.MethodGet, "/items/foo?id=1", nil), &target)
+
+		require.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		errs := multi.ErrorMap()
+		assert.Equal(t, 2, len(errs))
+		assert.IsType(t, &qparam.ParseError{}, errs["path:id"])
+		assert.IsType(t, &qparam.UnknownParameterError{}, errs["id"])
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/stoewer/go-qparam/internal"
+)
+
+// Validator is a functional option which registers a function that validates each target after
+// all parameters were read successfully, e.g. in order to integrate an external validation library
+// or to check constraints between fields. The function is called after the Validate methods of the
+// target (see Read). Errors for specific fields can be reported by a FieldError.
+func Validator(validate func(interface{}) error) Option {
+	return func(r *Reader) {
+		r.validator = validate
+	}
+}
+
+// validatable is implemented by structs with a Validate method
+type validatable interface {
+	Validate() error
+}
+
+// validate calls the Validate methods of all nested structs and slice elements bottom-up and
+// finally the Validate method of the target itself. Errors of the targets of Read are reported with
+// the name of their type, so that errors of several targets don't collide.
+func (r *Reader) validate(state *readState, prefix string, target reflect.Value, plan *internal.Plan) {
+	for i := len(plan.Fields) - 1; i >= 0; i-- {
+		field := &plan.Fields[i]
+		if !field.Struct && field.Elem == nil {
+			continue
+		}
+
+		value, ok := field.Value(target, false)
+		if !ok {
+			continue
+		}
+
+		name := r.config.Syntax.Join(prefix, field.Path)
+		if field.Elem != nil {
+			for j := 0; j < value.Len(); j++ {
+				elem := value.Index(j)
+				if elem.Kind() == reflect.Ptr {
+					if elem.IsNil() {
+						continue
+					}
+					elem = elem.Elem()
+				}
+				r.validate(state, r.config.Syntax.Join(name, strconv.Itoa(j)), elem, field.Elem)
+			}
+			continue
+		}
+
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		r.addValidationError(state, prefix, name, field.Name, plan, callValidate(value))
+	}
+
+	r.addValidationError(state, prefix, r.targetName(prefix, target), "", plan, callValidate(target))
+}
+
+// targetName returns the name which is used to report errors of the validated struct. For the targets
+// of Read this is the name of the type converted by the mapper (e.g. "line_item" for LineItem using
+// snake case), which is empty for anonymous structs.
+func (r *Reader) targetName(prefix string, target reflect.Value) string {
+	if prefix == "" {
+		return r.config.Mapper(target.Type().Name())
+	}
+	return prefix
+}
+
+// callValidate calls the Validate method of the struct if there is one
+func callValidate(value reflect.Value) error {
+	if value.CanAddr() {
+		value = value.Addr()
+	}
+	if v, ok := value.Interface().(validatable); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// addValidationError adds the error returned by the Validate method (or the validator) of the struct
+// with the provided name. Field errors are added with the name of the parameter of the field, parent
+// is the Go name of the struct in the plan. Field errors for unknown fields are added with the name
+// of the struct.
+func (r *Reader) addValidationError(state *readState, prefix, name, parent string, plan *internal.Plan, err error) {
+	if err == nil {
+		return
+	}
+
+	fieldErrs, others := splitFieldErrors(err)
+	for _, fieldErr := range fieldErrs {
+		fieldName := fieldErr.Field
+		if parent != "" {
+			fieldName = parent + "." + fieldName
+		}
+
+		param, cause := name, error(fieldErr)
+		for i := range plan.Fields {
+			field := &plan.Fields[i]
+			if field.Name != fieldName {
+				continue
+			}
+			param, cause = r.config.Syntax.Join(prefix, field.Path), fieldErr.Err
+			if field.Source != "" {
+				param = field.Source + ":" + field.Path
+			}
+			break
+		}
+
+		addValidationCause(state, param, cause)
+	}
+
+	for _, other := range others {
+		addValidationCause(state, name, other)
+	}
+}
+
+// addValidationCause adds a ValidationError for the parameter. If there is already such an error, e.g.
+// because several targets have the same name, the causes are joined.
+func addValidationCause(state *readState, param string, cause error) {
+	existing, ok := state.errors[param].(*ValidationError)
+	if !ok {
+		state.errors[param] = &ValidationError{Param: param, Cause: cause}
+		return
+	}
+
+	if joined, ok := existing.Cause.(joinedErrors); ok {
+		existing.Cause = append(joined, cause)
+	} else {
+		existing.Cause = joinedErrors{existing.Cause, cause}
+	}
+}
+
+// splitFieldErrors separates field errors from other errors. Errors which unwrap to multiple
+// errors (e.g. errors created by errors.Join) are split into their parts.
+func splitFieldErrors(err error) ([]*FieldError, []error) {
+	switch e := err.(type) {
+	case *FieldError:
+		return []*FieldError{e}, nil
+	case interface{ Unwrap() []error }:
+		var fieldErrs []*FieldError
+		var others []error
+		for _, part := range e.Unwrap() {
+			f, o := splitFieldErrors(part)
+			fieldErrs = append(fieldErrs, f...)
+			others = append(others, o...)
+		}
+		return fieldErrs, others
+	}
+	return nil, []error{err}
+}
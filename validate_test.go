@@ -0,0 +1,192 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stoewer/go-strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var calls []string
+
+type period struct {
+	From int
+	To   int
+}
+
+func (p *period) Validate() error {
+	calls = append(calls, "period")
+	if p.From > p.To {
+		return &qparam.FieldError{Field: "From", Err: errors.New("from must be before to")}
+	}
+	return nil
+}
+
+type lineItem struct {
+	SKU string
+	Qty int
+}
+
+func (item lineItem) Validate() error {
+	calls = append(calls, "item "+item.SKU)
+	if item.Qty == 0 {
+		return errors.New("empty item")
+	}
+	return nil
+}
+
+type report struct {
+	Name   string
+	Period *period
+	Items  []lineItem
+}
+
+func (r *report) Validate() error {
+	calls = append(calls, "report")
+	if r.Name == "" {
+		return errors.New("report without name")
+	}
+	return nil
+}
+
+func TestReader_Read_Validate(t *testing.T) {
+	reader := qparam.NewReader()
+
+	t.Run("valid", func(t *testing.T) {
+		calls = nil
+		values := url.Values{
+			"name":        []string{"sales"},
+			"period.from": []string{"1"},
+			"period.to":   []string{"2"},
+			"items.0.sku": []string{"A"},
+			"items.0.qty": []string{"1"},
+		}
+
+		err := reader.Read(values, &report{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"item A", "period", "report"}, calls, "validation must be bottom-up")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		values := url.Values{
+			"period.from": []string{"3"},
+			"period.to":   []string{"2"},
+			"items.0.sku": []string{"A"},
+			"items.0.qty": []string{"1"},
+			"items.5.sku": []string{"B"},
+		}
+
+		err := reader.Read(values, &report{})
+
+		require.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		errs := multi.ErrorMap()
+		assert.Equal(t, 3, len(errs))
+		assert.EqualError(t, errs["period.from"], "from must be before to")
+		assert.EqualError(t, errs["items.1"], "empty item")
+		assert.EqualError(t, errs["report"], "report without name")
+
+		var validationErr *qparam.ValidationError
+		require.True(t, errors.As(errs["period.from"], &validationErr))
+		assert.Equal(t, "period.from", validationErr.Param)
+	})
+
+	t.Run("several targets", func(t *testing.T) {
+		err := reader.Read(url.Values{"sku": []string{"A"}}, &report{}, &lineItem{})
+
+		require.Error(t, err)
+		errs := err.(qparam.MultiError).ErrorMap()
+		assert.Equal(t, 2, len(errs))
+		assert.EqualError(t, errs["report"], "report without name")
+		assert.EqualError(t, errs["lineitem"], "empty item")
+
+		snake := qparam.NewReader(qparam.Mapper(strcase.SnakeCase))
+		err = snake.Read(url.Values{"sku": []string{"A"}}, &lineItem{})
+
+		require.Error(t, err)
+		assert.EqualError(t, err.(qparam.MultiError).ErrorMap()["line_item"], "empty item")
+	})
+
+	t.Run("anonymous targets", func(t *testing.T) {
+		reader := qparam.NewReader(qparam.Validator(func(v interface{}) error {
+			return fmt.Errorf("invalid %T", v)
+		}))
+		first := struct{ A int }{}
+		second := struct{ B int }{}
+		err := reader.Read(url.Values{}, &first, &second)
+
+		require.Error(t, err)
+		errs := err.(qparam.MultiError).ErrorMap()
+		assert.Equal(t, 1, len(errs))
+		assert.EqualError(t, errs[""], "invalid *struct { A int }; invalid *struct { B int }")
+
+		var validationErr *qparam.ValidationError
+		require.True(t, errors.As(errs[""], &validationErr))
+		assert.Equal(t, "", validationErr.Param)
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		calls = nil
+		err := reader.Read(url.Values{"period.from": []string{"one"}}, &report{})
+
+		require.Error(t, err)
+		assert.Equal(t, 1, len(err.(qparam.MultiError).ErrorMap()))
+		assert.Empty(t, calls, "must not validate if parameters are invalid")
+	})
+}
+
+func TestValidator(t *testing.T) {
+	type search struct {
+		From   int `param:"from"`
+		To     int `param:"to"`
+		Period struct {
+			Start int
+		}
+	}
+
+	reader := qparam.NewReader(qparam.Validator(func(v interface{}) error {
+		s := v.(*search)
+		if s.From > s.To {
+			return joinErrors{
+				&qparam.FieldError{Field: "From", Err: errors.New("from must be before to")},
+				&qparam.FieldError{Field: "Period.Start", Err: errors.New("invalid")},
+				&qparam.FieldError{Field: "Unknown", Err: errors.New("unknown field")},
+			}
+		}
+		return nil
+	}))
+
+	err := reader.Read(url.Values{"from": []string{"2"}, "to": []string{"5"}}, &search{})
+	assert.NoError(t, err)
+
+	err = reader.Read(url.Values{"from": []string{"5"}, "to": []string{"2"}}, &search{})
+	require.Error(t, err)
+	multi, ok := err.(qparam.MultiError)
+	require.True(t, ok, "not a MultiError")
+	errs := multi.ErrorMap()
+	assert.Equal(t, 3, len(errs))
+	assert.EqualError(t, errs["from"], "from must be before to")
+	assert.EqualError(t, errs["period.start"], "invalid")
+	assert.EqualError(t, errs["search"], "Unknown: unknown field")
+}
+
+// joinErrors is an error which unwraps to multiple errors, like the errors created by errors.Join
+type joinErrors []error
+
+func (errs joinErrors) Error() string {
+	return "multiple errors"
+}
+
+func (errs joinErrors) Unwrap() []error {
+	return errs
+}
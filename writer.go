@@ -0,0 +1,199 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/stoewer/go-qparam/internal"
+)
+
+// Writer defines methods which convert the fields of source structs into query parameters.
+// It is the counterpart of the Reader: values written by a writer can be read back into a
+// struct of the same type by a reader with the same configuration.
+type Writer struct {
+	reader *Reader
+}
+
+// NewWriter creates a new writer which can be configured with the same functional options as
+// a reader. Options which only affect the reading of parameters (e.g. strict mode) are ignored.
+func NewWriter(options ...Option) *Writer {
+	return &Writer{reader: NewReader(options...)}
+}
+
+// Write converts the fields of the provided source structs into query parameters. Sources can
+// either be structs or pointers to structs. Fields with nil values are omitted.
+//
+// If an error occurs while formatting the values of struct fields, the returned error probably
+// implements the interface MultiError. In that case specific errors for each failed field
+// can be obtained from the error.
+func (w *Writer) Write(sources ...interface{}) (url.Values, error) {
+	params := url.Values{}
+
+	fieldErrors := multiError{}
+	for _, source := range sources {
+		sourceVal := reflect.ValueOf(source)
+		if sourceVal.Kind() == reflect.Ptr {
+			sourceVal = sourceVal.Elem()
+		}
+		if sourceVal.Kind() != reflect.Struct {
+			return nil, errors.New("source must be a struct")
+		}
+
+		// text marshalers with pointer receivers need addressable values
+		if !sourceVal.CanAddr() {
+			copied := reflect.New(sourceVal.Type()).Elem()
+			copied.Set(sourceVal)
+			sourceVal = copied
+		}
+
+		plan, err := w.reader.plan(sourceVal.Type())
+		if err != nil {
+			return nil, err
+		}
+
+		w.writeStruct(params, "", sourceVal, plan, fieldErrors)
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, fieldErrors
+	}
+
+	return params, nil
+}
+
+// writeStruct writes all fields of the source struct. The names of the parameters are prefixed if
+// the source is an element of a slice.
+func (w *Writer) writeStruct(params url.Values, prefix string, source reflect.Value, plan *internal.Plan,
+	fieldErrors multiError) {
+	for i := range plan.Fields {
+		field := &plan.Fields[i]
+		if field.Struct || field.File || field.Source != "" {
+			continue
+		}
+
+		value, ok := field.Value(source, false)
+		if !ok {
+			continue
+		}
+
+		name := w.reader.config.Syntax.Join(prefix, field.Path)
+		switch {
+		case field.Map:
+			w.writeMap(params, name, value, field, fieldErrors)
+		case field.Elem != nil:
+			w.writeIndexed(params, name, value, field, fieldErrors)
+		default:
+			err := w.writeValue(params, name, value, field)
+			if err != nil {
+				fieldErrors[name] = err
+			}
+		}
+	}
+}
+
+// writeIndexed writes the fields of each struct in the slice with the index of the element, e.g. "items.0.name"
+func (w *Writer) writeIndexed(params url.Values, name string, slice reflect.Value, field *internal.Field,
+	fieldErrors multiError) {
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+
+		w.writeStruct(params, w.reader.config.Syntax.Join(name, strconv.Itoa(i)), elem, field.Elem, fieldErrors)
+	}
+}
+
+// writeMap writes each map entry as parameter with the name of the map and the key, e.g. "labels.env"
+func (w *Writer) writeMap(params url.Values, name string, m reflect.Value, field *internal.Field,
+	fieldErrors multiError) {
+	if m.Len() > 0 && field.KeyFormatter == nil {
+		fieldErrors[name] = &UnsupportedTypeError{Param: name, Type: field.Type}
+		return
+	}
+
+	for _, key := range m.MapKeys() {
+		k, err := field.KeyFormatter.Format(key)
+		if err != nil {
+			fieldErrors[name] = err
+			continue
+		}
+
+		entry := w.reader.config.Syntax.Join(name, k)
+		err = w.writeValue(params, entry, m.MapIndex(key), field)
+		if err != nil {
+			fieldErrors[entry] = err
+		}
+	}
+}
+
+// writeValue formats the provided value of the field and adds it to the parameters.
+func (w *Writer) writeValue(params url.Values, name string, value reflect.Value, field *internal.Field) error {
+	if field.Slice {
+		return w.writeSlice(params, name, value, field)
+	}
+	return w.writeSingle(params, name, value, field)
+}
+
+func (w *Writer) writeSingle(params url.Values, name string, value reflect.Value, field *internal.Field) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if field.Formatter == nil {
+		return &UnsupportedTypeError{Param: name, Type: field.Type}
+	}
+
+	s, err := field.Formatter.Format(value)
+	if err != nil {
+		return err
+	}
+
+	params.Add(name, s)
+	return nil
+}
+
+func (w *Writer) writeSlice(params url.Values, name string, slice reflect.Value, field *internal.Field) error {
+	if slice.Len() == 0 {
+		return nil
+	}
+
+	if field.Formatter == nil {
+		return &UnsupportedTypeError{Param: name, Type: field.Type}
+	}
+
+	values := make([]string, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+
+		s, err := field.Formatter.Format(elem)
+		if err != nil {
+			return err
+		}
+		values = append(values, s)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	params[name] = append(params[name], internal.Join(values, field.Delimiter)...)
+	return nil
+}
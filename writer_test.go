@@ -0,0 +1,264 @@
+// Copyright (c) 2017, A. Stoewer <adrian@stoewer.me>
+// All rights reserved.
+
+package qparam_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stoewer/go-qparam"
+	"github.com/stoewer/go-strcase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Write(t *testing.T) {
+
+	type phone struct {
+		Label  string
+		Number string `param:"no"`
+	}
+
+	type contact struct {
+		Name     string
+		Age      uint8
+		Score    float64
+		Active   bool
+		Skip     string `param:"-"`
+		Birthday time.Time
+		Modified *time.Time
+		Phone    phone
+		Mobile   *phone
+		Tags     []string
+		Ranks    []*int
+		hidden   string
+	}
+
+	t.Run("no struct error", func(t *testing.T) {
+		source := "not a struct"
+
+		writer := qparam.NewWriter()
+		_, err := writer.Write(&source)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		source := struct {
+			Field complex64
+			Slice []map[string]string
+		}{Field: 1 + 2i, Slice: []map[string]string{{}}}
+
+		writer := qparam.NewWriter()
+		_, err := writer.Write(&source)
+
+		assert.Error(t, err)
+		multi, ok := err.(qparam.MultiError)
+		require.True(t, ok, "not a MultiError")
+		assert.Equal(t, 2, len(multi.ErrorMap()))
+	})
+
+	t.Run("struct values", func(t *testing.T) {
+		one := 1
+		source := contact{
+			Name:     "Doe",
+			Age:      31,
+			Score:    0.25,
+			Active:   true,
+			Skip:     "skipped",
+			Birthday: yesterday,
+			Phone:    phone{Label: "Home", Number: "+33 112 33445566"},
+			Tags:     []string{"a", "b"},
+			Ranks:    []*int{&one, nil, &one},
+			hidden:   "hidden",
+		}
+		expected := url.Values{
+			"name":        []string{"Doe"},
+			"age":         []string{"31"},
+			"score":       []string{"0.25"},
+			"active":      []string{"true"},
+			"birthday":    []string{yesterdayStr},
+			"phone.label": []string{"Home"},
+			"phone.no":    []string{"+33 112 33445566"},
+			"tags":        []string{"a", "b"},
+			"ranks":       []string{"1", "1"},
+		}
+
+		writer := qparam.NewWriter()
+		values, err := writer.Write(source)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+
+	t.Run("multiple structs", func(t *testing.T) {
+		first := phone{Label: "Home"}
+		second := struct{ Page int }{Page: 2}
+		expected := url.Values{
+			"label":  []string{"Home"},
+			"number": []string{""},
+			"page":   []string{"2"},
+		}
+
+		writer := qparam.NewWriter(qparam.Tag("other"))
+		values, err := writer.Write(&first, &second)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+
+	t.Run("collection formats", func(t *testing.T) {
+		type item struct {
+			SKU string
+		}
+
+		source := struct {
+			IDs   []int
+			Names []string `param:"names,pipes"`
+			Tags  []string `param:"tags,multi"`
+			Items []item
+		}{IDs: []int{1, 2}, Names: []string{"a", "b|c"}, Tags: []string{"d", "e"}, Items: []item{{SKU: "f"}}}
+		expected := url.Values{
+			"ids":         []string{"1,2"},
+			"names":       []string{`a|"b|c"`},
+			"tags":        []string{"d", "e"},
+			"items.0.sku": []string{"f"},
+		}
+
+		writer := qparam.NewWriter(qparam.Collection(qparam.CSV))
+		values, err := writer.Write(&source)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+
+	t.Run("map fields", func(t *testing.T) {
+		half := 0.5
+		source := struct {
+			Filter  map[string]string
+			Weights map[int]*float64
+			Labels  map[string][]string `param:"labels,csv"`
+		}{
+			Filter:  map[string]string{"status": "open", "owner": "bob"},
+			Weights: map[int]*float64{1: &half, 2: nil},
+			Labels:  map[string][]string{"env": {"dev", "prod"}},
+		}
+		expected := url.Values{
+			"filter.status": []string{"open"},
+			"filter.owner":  []string{"bob"},
+			"weights.1":     []string{"0.5"},
+			"labels.env":    []string{"dev,prod"},
+		}
+
+		writer := qparam.NewWriter()
+		values, err := writer.Write(&source)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+
+	t.Run("bracket syntax", func(t *testing.T) {
+		type item struct {
+			Name  string
+			Phone *phone
+		}
+
+		source := struct {
+			Phone  phone
+			Items  []*item
+			Labels map[string]string
+		}{
+			Phone:  phone{Label: "Home"},
+			Items:  []*item{{Name: "first", Phone: &phone{Label: "Mobile"}}, nil, {Name: "third"}},
+			Labels: map[string]string{"env": "prod"},
+		}
+		expected := url.Values{
+			"phone[label]":           []string{"Home"},
+			"phone[no]":              []string{""},
+			"items[0][name]":         []string{"first"},
+			"items[0][phone][label]": []string{"Mobile"},
+			"items[0][phone][no]":    []string{""},
+			"items[2][name]":         []string{"third"},
+			"labels[env]":            []string{"prod"},
+		}
+
+		writer := qparam.NewWriter(qparam.Brackets(true))
+		values, err := writer.Write(&source)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+
+	t.Run("embedded structs", func(t *testing.T) {
+		type Pagination struct {
+			Limit int
+		}
+
+		type Sorting struct {
+			Sort string
+		}
+
+		source := struct {
+			*Pagination
+			Sorting
+			Query string
+		}{Sorting: Sorting{Sort: "name"}, Query: "doe"}
+		expected := url.Values{
+			"sort":  []string{"name"},
+			"query": []string{"doe"},
+		}
+
+		writer := qparam.NewWriter()
+		values, err := writer.Write(&source)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, values)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		source := contact{
+			Name:     "Doe",
+			Birthday: yesterday,
+			Modified: &now,
+			Phone:    phone{Label: "Home", Number: "+33 112 33445566"},
+			Mobile:   &phone{Label: "Mobile", Number: "+33 999 12345678"},
+			Tags:     []string{"a", "b", "c"},
+		}
+
+		writer := qparam.NewWriter(qparam.Mapper(strcase.SnakeCase))
+		values, err := writer.Write(&source)
+		require.NoError(t, err)
+
+		target := contact{Mobile: &phone{}}
+		reader := qparam.NewReader(qparam.Mapper(strcase.SnakeCase))
+		err = reader.Read(values, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, source, target)
+	})
+}
+
+func BenchmarkWriter_Write(b *testing.B) {
+	type page struct {
+		Limit  int
+		Offset int
+	}
+
+	type filters struct {
+		Name    string
+		Tags    []string
+		Created time.Time
+	}
+
+	p := page{Limit: 25, Offset: 100}
+	f := filters{Name: "Doe", Tags: []string{"a", "b", "c"}, Created: now}
+	writer := qparam.NewWriter()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = writer.Write(&p, &f)
+	}
+}